@@ -0,0 +1,31 @@
+// Command elektra-http serves the key database over HTTP,
+// see package go.libelektra.org/server for the endpoints.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/server"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:33333", "address to listen on")
+	flag.Parse()
+
+	handle := elektra.New()
+
+	if err := handle.Open(); err != nil {
+		log.Fatalf("could not open KDB: %v", err)
+	}
+
+	defer handle.Close()
+
+	log.Printf("listening on %s", *addr)
+
+	if err := http.ListenAndServe(*addr, server.New(handle)); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// getKeys `Get`s the Key `name` and all Keys below it,
+// the caller has to close the KeySet and the parent Key.
+func (c *context) getKeys(name string) (elektra.KeySet, elektra.Key, error) {
+	parentKey, err := elektra.NewKey(name)
+
+	if err != nil {
+		return nil, nil, fail(exitInvalidArgs, "invalid key name %q", name)
+	}
+
+	ks := elektra.NewKeySet()
+
+	if _, err = c.handle.Get(ks, parentKey); err != nil {
+		ks.Close()
+		parentKey.Close()
+
+		return nil, nil, err
+	}
+
+	return ks, parentKey, nil
+}
+
+// lookup `Get`s the Key `name` and fails if it does not exist,
+// the caller has to close the KeySet and the parent Key.
+func (c *context) lookup(name string) (elektra.KeySet, elektra.Key, elektra.Key, error) {
+	ks, parentKey, err := c.getKeys(name)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key := ks.LookupByName(parentKey.Name())
+
+	if key == nil {
+		ks.Close()
+		parentKey.Close()
+
+		return nil, nil, nil, fail(exitKeyNotFound, "did not find key %q", name)
+	}
+
+	return ks, parentKey, key, nil
+}
+
+func get(c *context, args []string) error {
+	ks, parentKey, key, err := c.lookup(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	return c.output(toJSONKey(key, ""), key.String())
+}
+
+func set(c *context, args []string) error {
+	value := ""
+
+	if len(args) > 1 {
+		value = args[1]
+	}
+
+	ks, parentKey, err := c.getKeys(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	message := fmt.Sprintf("Set string to %q", value)
+	key := ks.LookupByName(parentKey.Name())
+
+	if key == nil {
+		name := parentKey.Name()
+
+		// like kdb, cascading keys are created in the user namespace
+		if parentKey.Namespace() == elektra.KEY_NS_CASCADING {
+			name = elektra.KEY_NS_USER.KeyName(name)
+		}
+
+		if key, err = elektra.NewKey(name, value); err != nil {
+			return fail(exitInvalidArgs, "invalid key name %q", name)
+		}
+
+		ks.AppendKey(key)
+		message = fmt.Sprintf("Create a new key %s with string %q", key.Name(), value)
+	} else if err = key.SetString(value); err != nil {
+		return err
+	}
+
+	if _, err = c.handle.Set(ks, parentKey); err != nil {
+		return err
+	}
+
+	return c.output(toJSONKey(key, ""), message)
+}
+
+func rm(c *context, args []string) error {
+	if c.recursive {
+		parentKey, err := elektra.NewKey(args[0])
+
+		if err != nil {
+			return fail(exitInvalidArgs, "invalid key name %q", args[0])
+		}
+
+		defer parentKey.Close()
+
+		return treeError(elektra.RemoveTreeKDB(c.handle, parentKey), args[0])
+	}
+
+	ks, parentKey, err := c.getKeys(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	removed := ks.RemoveByName(parentKey.Name())
+
+	if removed == nil {
+		return fail(exitKeyNotFound, "did not find key %q", args[0])
+	}
+
+	removed.Close()
+
+	_, err = c.handle.Set(ks, parentKey)
+
+	return err
+}
+
+func ls(c *context, args []string) error {
+	ks, parentKey, err := c.getKeys(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	names := []string{}
+
+	for _, k := range ks.ToSlice() {
+		if k.IsBelowOrSame(parentKey) {
+			names = append(names, k.Name())
+		}
+	}
+
+	return c.output(names, strings.Join(names, "\n"))
+}
+
+func metaName(name string) string {
+	return strings.TrimPrefix(name, "meta:/")
+}
+
+func metaGet(c *context, args []string) error {
+	ks, parentKey, key, err := c.lookup(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	value, ok := key.MetaMap()[metaName(args[1])]
+
+	if !ok {
+		return fail(exitMetaKeyNotFound, "did not find meta key %q of %q", args[1], args[0])
+	}
+
+	return c.output(map[string]string{metaName(args[1]): value}, value)
+}
+
+func metaSet(c *context, args []string) error {
+	ks, parentKey, err := c.getKeys(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	key := ks.LookupByName(parentKey.Name())
+
+	if key == nil {
+		if parentKey.Namespace() == elektra.KEY_NS_CASCADING {
+			return fail(exitKeyNotFound, "did not find key %q, cascading keys are not created", args[0])
+		}
+
+		if key, err = elektra.NewKey(parentKey.Name()); err != nil {
+			return err
+		}
+
+		ks.AppendKey(key)
+	}
+
+	if err = key.SetMeta(metaName(args[1]), args[2]); err != nil {
+		return err
+	}
+
+	_, err = c.handle.Set(ks, parentKey)
+
+	return err
+}
+
+func metaLs(c *context, args []string) error {
+	ks, parentKey, key, err := c.lookup(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	meta := key.MetaMap()
+	names := make([]string, 0, len(meta))
+
+	for name := range meta {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return c.output(meta, strings.Join(names, "\n"))
+}
+
+func metaRm(c *context, args []string) error {
+	ks, parentKey, key, err := c.lookup(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	if _, ok := key.MetaMap()[metaName(args[1])]; !ok {
+		return fail(exitMetaKeyNotFound, "did not find meta key %q of %q", args[1], args[0])
+	}
+
+	if err = key.RemoveMeta(metaName(args[1])); err != nil {
+		return err
+	}
+
+	_, err = c.handle.Set(ks, parentKey)
+
+	return err
+}
+
+func cp(c *context, args []string) error {
+	if !c.recursive {
+		return copyKey(c, args[0], args[1], false)
+	}
+
+	src, dst, err := srcAndDst(args)
+
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+	defer dst.Close()
+
+	return treeError(elektra.CopyTreeKDB(c.handle, src, dst), args[0])
+}
+
+func mv(c *context, args []string) error {
+	if !c.recursive {
+		return copyKey(c, args[0], args[1], true)
+	}
+
+	src, dst, err := srcAndDst(args)
+
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+	defer dst.Close()
+
+	return treeError(elektra.MoveTreeKDB(c.handle, src, dst), args[0])
+}
+
+func srcAndDst(args []string) (elektra.Key, elektra.Key, error) {
+	src, err := elektra.NewKey(args[0])
+
+	if err != nil {
+		return nil, nil, fail(exitInvalidArgs, "invalid key name %q", args[0])
+	}
+
+	dst, err := elektra.NewKey(args[1])
+
+	if err != nil {
+		src.Close()
+		return nil, nil, fail(exitInvalidArgs, "invalid key name %q", args[1])
+	}
+
+	return src, dst, nil
+}
+
+// treeError converts errors of the tree functions to the exit codes of kdb.
+func treeError(err error, name string) error {
+	if errors.Is(err, elektra.ErrKeyNotFound) {
+		return fail(exitKeyNotFound, "did not find key %q", name)
+	}
+
+	return err
+}
+
+// copyKey copies the Key `src` to `dst` and removes
+// the source Key if `move` is true.
+func copyKey(c *context, src, dst string, move bool) error {
+	ks, srcKey, err := c.getKeys(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer srcKey.Close()
+
+	dstKey, err := elektra.NewKey(dst)
+
+	if err != nil {
+		return fail(exitInvalidArgs, "invalid key name %q", dst)
+	}
+
+	defer dstKey.Close()
+
+	if _, err = c.handle.Get(ks, dstKey); err != nil {
+		return err
+	}
+
+	source := ks.LookupByName(srcKey.Name())
+
+	if source == nil {
+		return fail(exitKeyNotFound, "did not find key %q", src)
+	}
+
+	name := dstKey.Name()
+
+	// like set, cascading keys are created in the user namespace
+	if dstKey.Namespace() == elektra.KEY_NS_CASCADING {
+		name = elektra.KEY_NS_USER.KeyName(name)
+	}
+
+	copied := source.Duplicate(elektra.KEY_CP_ALL)
+
+	if err = copied.SetName(name); err != nil {
+		copied.Close()
+		return err
+	}
+
+	if move {
+		ks.Remove(source).Close()
+	}
+
+	ks.AppendKey(copied)
+
+	// a single Set suffices if one parent contains the other,
+	// otherwise write the destination first so nothing gets lost
+	switch {
+	case dstKey.IsBelowOrSame(srcKey):
+		_, err = c.handle.Set(ks, srcKey)
+	case srcKey.IsBelow(dstKey):
+		_, err = c.handle.Set(ks, dstKey)
+	default:
+		if _, err = c.handle.Set(ks, dstKey); err == nil && move {
+			_, err = c.handle.Set(ks, srcKey)
+		}
+	}
+
+	return err
+}
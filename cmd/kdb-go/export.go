@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// jsonKey is the JSON representation of a Key used by the JSON
+// output mode and by export and import.
+type jsonKey struct {
+	Name  string            `json:"name"`
+	Value string            `json:"value"`
+	Meta  map[string]string `json:"meta,omitempty"`
+}
+
+// toJSONKey converts `key`, its name is made relative to `root` if set.
+func toJSONKey(key elektra.Key, root string) jsonKey {
+	name := key.Name()
+
+	if root != "" {
+		name = strings.TrimPrefix(strings.TrimPrefix(name, root), "/")
+	}
+
+	meta := key.MetaMap()
+
+	if len(meta) == 0 {
+		meta = nil
+	}
+
+	return jsonKey{Name: name, Value: key.String(), Meta: meta}
+}
+
+// export writes the Keys below `name` as JSON, the names are relative
+// to `name` so they can be imported below another Key. Cascading Keys
+// are rejected as Keys of different namespaces would get the same name.
+func export(c *context, args []string) error {
+	if strings.HasPrefix(args[0], "/") {
+		return fail(exitInvalidArgs, "cannot export the cascading key %q, use a namespace", args[0])
+	}
+
+	ks, parentKey, err := c.getKeys(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	keys := []jsonKey{}
+
+	for _, k := range ks.ToSlice() {
+		if k.IsBelowOrSame(parentKey) {
+			keys = append(keys, toJSONKey(k, parentKey.Name()))
+		}
+	}
+
+	w := c.stdout
+
+	if len(args) > 1 {
+		f, err := os.Create(args[1])
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		w = f
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(keys)
+}
+
+// importKeys reads Keys written by export and stores them below `name`,
+// below a cascading `name` they are stored in the user namespace.
+func importKeys(c *context, args []string) error {
+	var r io.Reader = c.stdin
+
+	if len(args) > 1 {
+		f, err := os.Open(args[1])
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		r = f
+	}
+
+	var keys []jsonKey
+
+	if err := json.NewDecoder(r).Decode(&keys); err != nil {
+		return fail(exitInvalidArgs, "invalid import data: %v", err)
+	}
+
+	ks, parentKey, err := c.getKeys(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	root := parentKey.Name()
+
+	// like kdb, cascading keys are created in the user namespace
+	if parentKey.Namespace() == elektra.KEY_NS_CASCADING {
+		root = elektra.KEY_NS_USER.KeyName(root)
+	}
+
+	for _, jk := range keys {
+		name := root
+
+		if jk.Name != "" {
+			name = strings.TrimSuffix(name, "/") + "/" + jk.Name
+		}
+
+		key, err := elektra.NewKey(name, jk.Value)
+
+		if err != nil {
+			return fail(exitInvalidArgs, "invalid key name %q", name)
+		}
+
+		for metaName, value := range jk.Meta {
+			if err = key.SetMeta(metaName, value); err != nil {
+				return err
+			}
+		}
+
+		ks.AppendKey(key)
+	}
+
+	_, err = c.handle.Set(ks, parentKey)
+
+	return err
+}
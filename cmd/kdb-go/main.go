@@ -0,0 +1,287 @@
+// Command kdb-go is a Go port of the most common commands of
+// libelektra's `kdb` tool.
+//
+// Usage:
+//
+//	kdb-go <command> [options] <arguments>
+//
+// The commands get, set, rm, ls, meta-get, meta-set, meta-ls, meta-rm,
+// export, import, cp and mv are supported. Every command accepts `-json`
+// to print its output and errors as JSON. Options have to precede the
+// arguments, e.g. `kdb-go cp -r <source> <destination>`; use `--` before
+// arguments that look like options.
+//
+// Exit codes follow `kdb`:
+//
+//	0   success
+//	1   help text was shown
+//	2   invalid options
+//	3   invalid arguments
+//	4   unknown command
+//	5   error while accessing the key database
+//	11  key not found
+//	12  meta key not found
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+const (
+	exitOK              = 0
+	exitHelp            = 1
+	exitInvalidOptions  = 2
+	exitInvalidArgs     = 3
+	exitUnknownCommand  = 4
+	exitKDBError        = 5
+	exitKeyNotFound     = 11
+	exitMetaKeyNotFound = 12
+)
+
+// exitError carries the exit code of a failed command.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func fail(code int, format string, args ...interface{}) error {
+	return &exitError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+type command struct {
+	usage     string
+	args      int
+	maxArgs   int
+	recursive bool
+	run       func(c *context, args []string) error
+}
+
+var commands = map[string]command{
+	"get":      {"get <name>", 1, 1, false, get},
+	"set":      {"set <name> [<value>]", 1, 2, false, set},
+	"rm":       {"rm [-r] <name>", 1, 1, true, rm},
+	"ls":       {"ls <name>", 1, 1, false, ls},
+	"meta-get": {"meta-get <name> <meta-name>", 2, 2, false, metaGet},
+	"meta-set": {"meta-set <name> <meta-name> <meta-value>", 3, 3, false, metaSet},
+	"meta-ls":  {"meta-ls <name>", 1, 1, false, metaLs},
+	"meta-rm":  {"meta-rm <name> <meta-name>", 2, 2, false, metaRm},
+	"export":   {"export <name> [<file>]", 1, 2, false, export},
+	"import":   {"import <name> [<file>]", 1, 2, false, importKeys},
+	"cp":       {"cp [-r] <source> <destination>", 2, 2, true, cp},
+	"mv":       {"mv [-r] <source> <destination>", 2, 2, true, mv},
+}
+
+// context is passed to every command.
+type context struct {
+	handle    elektra.KDB
+	json      bool
+	recursive bool
+	stdin     io.Reader
+	stdout    io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) < 1 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr)
+		return exitHelp
+	}
+
+	cmd, ok := commands[args[0]]
+
+	if !ok {
+		fmt.Fprintf(stderr, "kdb-go: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return exitUnknownCommand
+	}
+
+	c := &context{stdin: stdin, stdout: stdout}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.BoolVar(&c.json, "json", false, "print output and errors as JSON")
+
+	if cmd.recursive {
+		flags.BoolVar(&c.recursive, "r", false, "work recursively")
+	}
+
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: kdb-go %s\n", cmd.usage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitHelp
+		}
+
+		return exitInvalidOptions
+	}
+
+	if arg := misplacedOption(flags, args[1:]); arg != "" {
+		fmt.Fprintf(stderr, "kdb-go: option %s has to precede the arguments\n", arg)
+		flags.Usage()
+
+		return exitInvalidOptions
+	}
+
+	if flags.NArg() < cmd.args || flags.NArg() > cmd.maxArgs {
+		flags.Usage()
+		return exitInvalidArgs
+	}
+
+	c.handle = elektra.New()
+
+	if err := c.handle.Open(); err != nil {
+		return printError(c, stderr, err)
+	}
+
+	defer c.handle.Close()
+
+	if err := cmd.run(c, flags.Args()); err != nil {
+		return printError(c, stderr, err)
+	}
+
+	return exitOK
+}
+
+// misplacedOption returns the first argument after the parsed options that
+// is one of the options, which the flag package would silently ignore.
+// Arguments after `--` are never options.
+func misplacedOption(flags *flag.FlagSet, args []string) string {
+	rest := flags.Args()
+
+	if parsed := len(args) - len(rest); parsed > 0 && args[parsed-1] == "--" {
+		return ""
+	}
+
+	for _, arg := range rest {
+		name := strings.TrimLeft(arg, "-")
+
+		if i := strings.Index(name, "="); i >= 0 {
+			name = name[:i]
+		}
+
+		if strings.HasPrefix(arg, "-") && flags.Lookup(name) != nil {
+			return arg
+		}
+	}
+
+	return ""
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: kdb-go <command> [options] <arguments>")
+	fmt.Fprintln(w, "\nCommands:")
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+}
+
+// printError prints `err` to `w` and returns the exit code for it,
+// errors of the key database are printed with all of their details.
+func printError(c *context, w io.Writer, err error) int {
+	code := exitKDBError
+
+	var exitErr *exitError
+
+	if errors.As(err, &exitErr) {
+		code = exitErr.code
+	}
+
+	var elektraErr *elektra.ElektraError
+
+	if !errors.As(err, &elektraErr) {
+		if c.json {
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		} else {
+			fmt.Fprintf(w, "Sorry, %v\n", err)
+		}
+
+		return code
+	}
+
+	if c.json {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{
+			"number":      elektraErr.Number,
+			"description": elektraErr.Description,
+			"reason":      elektraErr.Reason,
+			"ingroup":     elektraErr.Ingroup,
+			"module":      elektraErr.Module,
+			"file":        elektraErr.File,
+			"line":        elektraErr.Line,
+			"mountpoint":  elektraErr.Mountpoint,
+			"configfile":  elektraErr.ConfigFile,
+		}})
+
+		return code
+	}
+
+	fmt.Fprintf(w, "Sorry, module %s issued the error %s:\n", elektraErr.Module, elektraErr.Number)
+	fmt.Fprintf(w, "%s: %s\n", elektraErr.Description, elektraErr.Reason)
+
+	if elektraErr.ConfigFile != "" {
+		fmt.Fprintf(w, "Configfile: %s\n", elektraErr.ConfigFile)
+	}
+
+	if elektraErr.Mountpoint != "" {
+		fmt.Fprintf(w, "Mountpoint: %s\n", elektraErr.Mountpoint)
+	}
+
+	if elektraErr.Ingroup != "" {
+		fmt.Fprintf(w, "Ingroup: %s\n", elektraErr.Ingroup)
+	}
+
+	if elektraErr.File != "" {
+		fmt.Fprintf(w, "At: %s:%d\n", elektraErr.File, elektraErr.Line)
+	}
+
+	return code
+}
+
+// output prints `v` as JSON in JSON mode or `text` otherwise.
+func (c *context) output(v interface{}, text string) error {
+	if c.json {
+		encoder := json.NewEncoder(c.stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(v)
+	}
+
+	if text == "" {
+		return nil
+	}
+
+	_, err := fmt.Fprintln(c.stdout, strings.TrimSuffix(text, "\n"))
+
+	return err
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	. "go.libelektra.org/test"
+)
+
+var usageTests = []struct {
+	args     []string
+	expected int
+}{
+	{[]string{}, exitHelp},
+	{[]string{"help"}, exitHelp},
+	{[]string{"unknown"}, exitUnknownCommand},
+	{[]string{"get"}, exitInvalidArgs},
+	{[]string{"get", "/a", "/b"}, exitInvalidArgs},
+	{[]string{"get", "-r", "/a"}, exitInvalidOptions},
+	{[]string{"meta-set", "/a", "meta"}, exitInvalidArgs},
+	{[]string{"cp", "/a", "/b", "-r"}, exitInvalidOptions},
+	{[]string{"get", "/a", "-json=true"}, exitInvalidOptions},
+	{[]string{"export", "/a"}, exitInvalidArgs},
+}
+
+func TestUsageExitCodes(t *testing.T) {
+	for _, test := range usageTests {
+		t.Run(fmt.Sprintf("%q", test.args), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(test.args, nil, &stdout, &stderr)
+			Assertf(t, code == test.expected, "exit code should be %d but is %d", test.expected, code)
+		})
+	}
+}
+
+var jsonKeyTests = []struct {
+	name     string
+	root     string
+	expected string
+}{
+	{"user:/tests/go/elektra/export/a", "", "user:/tests/go/elektra/export/a"},
+	{"user:/tests/go/elektra/export/a", "user:/tests/go/elektra/export", "a"},
+	{"user:/tests/go/elektra/export/a/b", "user:/tests/go/elektra/export", "a/b"},
+	{"user:/tests/go/elektra/export", "user:/tests/go/elektra/export", ""},
+}
+
+func TestToJSONKey(t *testing.T) {
+	for _, test := range jsonKeyTests {
+		k, err := elektra.NewKey(test.name, "value")
+		Check(t, err, "could not create key")
+
+		jk := toJSONKey(k, test.root)
+		Assertf(t, jk.Name == test.expected, "name relative to %q should be %q but is %q", test.root, test.expected, jk.Name)
+		Assert(t, jk.Value == "value", "wrong value")
+		Assert(t, jk.Meta == nil, "meta should be omitted")
+	}
+}
@@ -1,3 +1,3 @@
 module go.libelektra.org
 
-go 1.13
+go 1.21
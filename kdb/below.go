@@ -0,0 +1,21 @@
+//go:build !elektra_legacy
+
+package kdb
+
+// #include <kdb.h>
+import "C"
+
+// haveKsBelow reports whether ksBelow and ksFindHierarchy of libelektra are used.
+const haveKsBelow = true
+
+func ksBelow(ks *C.struct__KeySet, root *C.struct__Key) *C.struct__KeySet {
+	return C.ksBelow(ks, root)
+}
+
+// ksFindHierarchy returns the range of the Keys below or the same as `root`.
+func ksFindHierarchy(ks *C.struct__KeySet, root *C.struct__Key) (C.elektraCursor, C.elektraCursor) {
+	var end C.elektraCursor
+	start := C.ksFindHierarchy(ks, root, &end)
+
+	return start, end
+}
@@ -0,0 +1,46 @@
+package kdb
+
+// #include <kdb.h>
+//
+// static KeySet * ksNewEmpty(void) {
+// 	 return ksNew(0, KEY_END);
+// }
+import "C"
+
+// ksBelowIterate works like ksBelow but iterates over the whole KeySet,
+// it is used for libelektra releases without ksBelow.
+func ksBelowIterate(ks *C.struct__KeySet, root *C.struct__Key) *C.struct__KeySet {
+	below := C.ksNewEmpty()
+
+	for it := C.elektraCursor(0); it < C.elektraCursor(C.ksGetSize(ks)); it++ {
+		k := C.ksAtCursor(ks, it)
+
+		if C.keyIsBelowOrSame(root, k) == 1 {
+			C.ksAppendKey(below, k)
+		}
+	}
+
+	return below
+}
+
+// ksFindHierarchyIterate works like ksFindHierarchy but iterates over
+// the KeySet, it is used for libelektra releases without ksFindHierarchy.
+func ksFindHierarchyIterate(ks *C.struct__KeySet, root *C.struct__Key) (C.elektraCursor, C.elektraCursor) {
+	size := C.elektraCursor(C.ksGetSize(ks))
+	start := size
+
+	for it := C.elektraCursor(0); it < size; it++ {
+		if C.keyIsBelowOrSame(root, C.ksAtCursor(ks, it)) == 1 {
+			start = it
+			break
+		}
+	}
+
+	end := start
+
+	for end < size && C.keyIsBelowOrSame(root, C.ksAtCursor(ks, end)) == 1 {
+		end++
+	}
+
+	return start, end
+}
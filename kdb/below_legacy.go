@@ -0,0 +1,19 @@
+//go:build elektra_legacy
+
+package kdb
+
+// #include <kdb.h>
+import "C"
+
+// haveKsBelow reports whether ksBelow and ksFindHierarchy of libelektra are used.
+// With the elektra_legacy tag they are emulated by iterating over the KeySet.
+const haveKsBelow = false
+
+func ksBelow(ks *C.struct__KeySet, root *C.struct__Key) *C.struct__KeySet {
+	return ksBelowIterate(ks, root)
+}
+
+// ksFindHierarchy returns the range of the Keys below or the same as `root`.
+func ksFindHierarchy(ks *C.struct__KeySet, root *C.struct__Key) (C.elektraCursor, C.elektraCursor) {
+	return ksFindHierarchyIterate(ks, root)
+}
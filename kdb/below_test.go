@@ -0,0 +1,48 @@
+package kdb
+
+import (
+	"reflect"
+	"testing"
+
+	. "go.libelektra.org/test"
+)
+
+func keyNames(ks KeySet) []string {
+	var names []string
+
+	for _, k := range ks.ToSlice() {
+		names = append(names, k.Name())
+	}
+
+	return names
+}
+
+func TestBelowIterate(t *testing.T) {
+	ks := NewKeySet().(*CKeySet)
+	defer ks.Close()
+
+	for _, name := range []string{"system:/tests/go/a", "user:/tests/go", "user:/tests/go/a", "user:/tests/go/a/b", "user:/tests/go/ab", "user:/tests/got"} {
+		k, err := newKey(name)
+		Check(t, err, "could not create key")
+		ks.AppendKey(k)
+	}
+
+	for _, name := range []string{"user:/tests/go", "user:/tests/go/a", "user:/tests/go/c", "user:/", "system:/tests/go"} {
+		root, err := newKey(name)
+		Check(t, err, "could not create key")
+
+		native := wrapKeySet(ksBelow(ks.Ptr, root.Ptr))
+		iterated := wrapKeySet(ksBelowIterate(ks.Ptr, root.Ptr))
+
+		Assertf(t, reflect.DeepEqual(keyNames(native), keyNames(iterated)), "ksBelow of %q returned %v but iterating %v", name, keyNames(native), keyNames(iterated))
+
+		nativeStart, nativeEnd := ksFindHierarchy(ks.Ptr, root.Ptr)
+		iteratedStart, iteratedEnd := ksFindHierarchyIterate(ks.Ptr, root.Ptr)
+
+		Assertf(t, nativeStart == iteratedStart && nativeEnd == iteratedEnd, "ksFindHierarchy of %q returned [%d, %d) but iterating [%d, %d)", name, nativeStart, nativeEnd, iteratedStart, iteratedEnd)
+
+		native.Close()
+		iterated.Close()
+		root.Close()
+	}
+}
@@ -58,3 +58,44 @@ func BenchmarkKeySetSlowerSliceRangeIterator(b *testing.B) {
 		}
 	}
 }
+
+func benchmarkBelowParent(b *testing.B) Key {
+	b.Helper()
+
+	parent, err := NewKey("proc:/tests/go/elektra/benchmark/iterator/callback/00000500")
+	Checkf(b, err, "kdb.NewKey() failed: %v", err)
+
+	return parent
+}
+
+func BenchmarkKeySetDuplicateCut(b *testing.B) {
+	ks := setupTestData(b, dataSize)
+	parent := benchmarkBelowParent(b)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		dup := ks.Duplicate()
+		dup.Cut(parent).Close()
+		dup.Close()
+	}
+}
+
+func BenchmarkKeySetBelow(b *testing.B) {
+	ks := setupTestData(b, dataSize)
+	parent := benchmarkBelowParent(b)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		ks.Below(parent).Close()
+	}
+}
+
+func BenchmarkKeySetHasBelow(b *testing.B) {
+	ks := setupTestData(b, dataSize)
+	parent := benchmarkBelowParent(b)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		ks.HasBelow(parent)
+	}
+}
@@ -0,0 +1,190 @@
+package kdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidDotenv = errors.New("invalid dotenv line")
+
+// EnvMapping configures how OverlayEnv maps variables to Key names.
+type EnvMapping struct {
+	// Separator separates the parts of the Key name, "__" by default.
+	Separator string
+	// Parent is the Key the variables are stored below, by default
+	// proc:/ followed by the lowercased prefix without trailing "_".
+	Parent string
+	// KeepCase disables lowercasing of the Key names.
+	KeepCase bool
+	// KeepNumbers disables the conversion of numeric parts to
+	// array indices, e.g. SERVERS__1 is mapped to servers/#1.
+	KeepNumbers bool
+	// Environ are the variables in the form "NAME=value",
+	// by default the variables of the process are used.
+	Environ []string
+}
+
+// OverlayEnv adds a proc:/ Key for every environment variable that starts
+// with `prefix`, e.g. with the prefix "MYAPP_" the variable MYAPP_DB__HOST
+// is mapped to proc:/myapp/db/host. Since proc:/ is the first namespace
+// of a cascading lookup these Keys override all other namespaces.
+func OverlayEnv(ks KeySet, prefix string, mapping ...EnvMapping) error {
+	var m EnvMapping
+
+	if len(mapping) > 0 {
+		m = mapping[0]
+	}
+
+	if m.Separator == "" {
+		m.Separator = "__"
+	}
+
+	if m.Parent == "" {
+		m.Parent = KEY_NS_PROC.KeyName(strings.ToLower(strings.TrimRight(prefix, "_")))
+	}
+
+	if m.Environ == nil {
+		m.Environ = os.Environ()
+	}
+
+	for _, variable := range m.Environ {
+		i := strings.Index(variable, "=")
+
+		if i < 0 || !strings.HasPrefix(variable[:i], prefix) || len(variable[:i]) == len(prefix) {
+			continue
+		}
+
+		name := m.keyName(variable[len(prefix):i])
+		key, err := NewKey(name, variable[i+1:])
+
+		if err != nil {
+			return fmt.Errorf("could not map %s to %q: %w", variable[:i], name, err)
+		}
+
+		ks.AppendKey(key)
+	}
+
+	return nil
+}
+
+// keyName converts the variable name without prefix to a Key name.
+func (m EnvMapping) keyName(variable string) string {
+	parts := strings.Split(variable, m.Separator)
+
+	for i, part := range parts {
+		if !m.KeepNumbers {
+			if n, err := strconv.Atoi(part); err == nil && n >= 0 {
+				parts[i] = ArrayIndex(n)
+				continue
+			}
+		}
+
+		if !m.KeepCase {
+			parts[i] = strings.ToLower(part)
+		}
+	}
+
+	return strings.TrimSuffix(m.Parent, "/") + "/" + strings.Join(parts, "/")
+}
+
+// LoadDotenv reads the .env file `path`, see ParseDotenv.
+func LoadDotenv(path string) ([]string, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return ParseDotenv(f)
+}
+
+// ParseDotenv parses variables in the .env format and returns them in the
+// form "NAME=value" so they can be passed to OverlayEnv as EnvMapping.Environ.
+// Empty lines, comments and the `export` keyword are ignored, values may be
+// quoted and double quoted values support the escapes \n, \t, \" and \\.
+func ParseDotenv(r io.Reader) ([]string, error) {
+	var variables []string
+
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		text = strings.TrimPrefix(text, "export ")
+		i := strings.Index(text, "=")
+
+		if i <= 0 {
+			return nil, fmt.Errorf("%w %d: %q", ErrInvalidDotenv, line, text)
+		}
+
+		name := strings.TrimSpace(text[:i])
+		value, err := dotenvValue(strings.TrimSpace(text[i+1:]))
+
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %v", ErrInvalidDotenv, line, err)
+		}
+
+		variables = append(variables, name+"="+value)
+	}
+
+	return variables, scanner.Err()
+}
+
+func dotenvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch quote := value[0]; quote {
+	case '\'':
+		end := strings.IndexByte(value[1:], quote)
+
+		if end < 0 {
+			return "", errors.New("missing closing quote")
+		}
+
+		return value[1 : end+1], nil
+	case '"':
+		var b strings.Builder
+
+		for i := 1; i < len(value); i++ {
+			switch c := value[i]; {
+			case c == '"':
+				return b.String(), nil
+			case c == '\\' && i+1 < len(value):
+				i++
+
+				switch value[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(value[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+
+		return "", errors.New("missing closing quote")
+	}
+
+	// unquoted values end at an inline comment
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+
+	return strings.TrimSpace(value), nil
+}
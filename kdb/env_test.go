@@ -0,0 +1,65 @@
+package kdb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	. "go.libelektra.org/test"
+)
+
+func TestOverlayEnv(t *testing.T) {
+	userKey, _ := elektra.NewKey("user:/myapp/db/host", "user")
+	ks := elektra.NewKeySet(userKey)
+
+	err := elektra.OverlayEnv(ks, "MYAPP_", elektra.EnvMapping{
+		Environ: []string{
+			"MYAPP_DB__HOST=env",
+			"MYAPP_SERVERS__10__NAME=web",
+			"OTHER_DB__HOST=ignored",
+		},
+	})
+	Check(t, err, "OverlayEnv failed")
+
+	key := ks.LookupByName("/myapp/db/host")
+	Assertf(t, key.Name() == "proc:/myapp/db/host", "cascading lookup should find the env Key but found %q", key.Name())
+	Assert(t, key.String() == "env", "wrong value")
+	Assert(t, ks.LookupByName("proc:/myapp/servers/#_10/name") != nil, "numbers should be mapped to array indices")
+	Assertf(t, ks.Len() == 3, "expected 3 Keys but got %d", ks.Len())
+}
+
+func TestOverlayEnvMapping(t *testing.T) {
+	ks := elektra.NewKeySet()
+
+	err := elektra.OverlayEnv(ks, "APP_", elektra.EnvMapping{
+		Separator:   "_",
+		Parent:      "proc:/sw/app",
+		KeepCase:    true,
+		KeepNumbers: true,
+		Environ:     []string{"APP_Port_1=80"},
+	})
+	Check(t, err, "OverlayEnv failed")
+	Assert(t, ks.LookupByName("proc:/sw/app/Port/1") != nil, "mapping was not applied")
+}
+
+func TestParseDotenv(t *testing.T) {
+	variables, err := elektra.ParseDotenv(strings.NewReader(`
+# comment
+export MYAPP_A=plain # inline comment
+MYAPP_B='single # quoted'
+MYAPP_C="line\nbreak"
+MYAPP_D=
+`))
+	Check(t, err, "ParseDotenv failed")
+
+	expected := []string{"MYAPP_A=plain", "MYAPP_B=single # quoted", "MYAPP_C=line\nbreak", "MYAPP_D="}
+	Assertf(t, len(variables) == len(expected), "expected %d variables but got %d", len(expected), len(variables))
+
+	for i, v := range expected {
+		Assertf(t, variables[i] == v, "variable %d should be %q but is %q", i, v, variables[i])
+	}
+
+	_, err = elektra.ParseDotenv(strings.NewReader("MYAPP_E=\"unterminated"))
+	Assert(t, errors.Is(err, elektra.ErrInvalidDotenv), "unterminated quote should return ErrInvalidDotenv")
+}
@@ -2,6 +2,9 @@ package kdb
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"errors"
 )
@@ -14,7 +17,9 @@ type ElektraError struct {
 	Ingroup     string
 	Module      string
 	File        string
-	Line        string
+	Line        int
+	Mountpoint  string
+	ConfigFile  string
 }
 
 func (e *ElektraError) Error() string {
@@ -25,51 +30,233 @@ func (e *ElektraError) Unwrap() error {
 	return e.Err
 }
 
-func errFromKey(k *CKey) error {
-	description := k.Meta("error/description")
-	number := k.Meta("error/number")
-	reason := k.Meta("error/reason")
-	ingroup := k.Meta("error/ingroup")
-	module := k.Meta("error/module")
-	file := k.Meta("error/file")
-	line := k.Meta("error/line")
+// Is reports whether the error code of this error is `target`
+// or one of the categories it belongs to, e.g. an `ErrOutOfMemory`
+// error is also an `ErrResource` error.
+func (e *ElektraError) Is(target error) bool {
+	return codeIs(e.Number, target)
+}
+
+// As fills the category error types `ResourceError`, `InstallationError`,
+// `LogicalError`, `ConflictingStateError` and `ValidationError` if this
+// error belongs to the respective category.
+func (e *ElektraError) As(target interface{}) bool {
+	switch t := target.(type) {
+	case *ResourceError:
+		if codeIs(e.Number, ErrResource) {
+			*t = ResourceError{e}
+			return true
+		}
+	case *InstallationError:
+		if codeIs(e.Number, ErrInstallation) {
+			*t = InstallationError{e}
+			return true
+		}
+	case *LogicalError:
+		if codeIs(e.Number, ErrLogical) {
+			*t = LogicalError{e}
+			return true
+		}
+	case *ConflictingStateError:
+		if codeIs(e.Number, ErrConflictingState) {
+			*t = ConflictingStateError{e}
+			return true
+		}
+	case *ValidationError:
+		if codeIs(e.Number, ErrValidation) {
+			*t = ValidationError{e}
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResourceError is an `ElektraError` of the Resource category.
+type ResourceError struct{ *ElektraError }
+
+// InstallationError is an `ElektraError` of the Installation category.
+type InstallationError struct{ *ElektraError }
 
-	err := errCodeMap[number]
+// LogicalError is an `ElektraError` of the Logical category.
+type LogicalError struct{ *ElektraError }
+
+// ConflictingStateError is an `ElektraError` of the ConflictingState category.
+type ConflictingStateError struct{ *ElektraError }
+
+// ValidationError is an `ElektraError` of the Validation category.
+type ValidationError struct{ *ElektraError }
+
+// NewElektraError creates an ElektraError with the error code `code`,
+// e.g. NewElektraError(ErrConflictingState, "key was modified").
+func NewElektraError(code error, reason string) *ElektraError {
+	number, description := splitCode(code)
 
 	return &ElektraError{
-		Err:         err,
-		Description: description,
+		Err:         code,
 		Number:      number,
+		Description: description,
 		Reason:      reason,
-		Ingroup:     ingroup,
-		Module:      module,
-		File:        file,
-		Line:        line,
 	}
 }
 
+// splitCode splits error codes like "C03200 - ValidationSemantic"
+// into number and description.
+func splitCode(code error) (string, string) {
+	parts := strings.SplitN(code.Error(), " - ", 2)
+
+	if len(parts) != 2 {
+		return "", code.Error()
+	}
+
+	return parts[0], parts[1]
+}
+
+func errFromKey(k *CKey) error {
+	err := problemFromFields(func(field string) string {
+		return k.Meta("error/" + field)
+	})
+
+	return &err
+}
+
+// problemFromFields creates an ElektraError from the meta
+// Keys below `error` or `warnings/#N`, e.g. `number`.
+func problemFromFields(field func(name string) string) ElektraError {
+	number := field("number")
+
+	return ElektraError{
+		Err:         errCodeMap[number],
+		Description: field("description"),
+		Number:      number,
+		Reason:      field("reason"),
+		Ingroup:     field("ingroup"),
+		Module:      field("module"),
+		File:        field("file"),
+		Line:        parseLine(field("line")),
+		Mountpoint:  field("mountpoint"),
+		ConfigFile:  field("configfile"),
+	}
+}
+
+// ElektraWarning is a non-fatal problem that Elektra reported
+// on the parent Key during Get or Set. It has the same fields
+// as ElektraError, but is not returned as error.
+type ElektraWarning struct {
+	ElektraError
+}
+
+// warningsFromKey collects all warnings stored as `warnings/#N/*`
+// meta Keys in the order of their array indices.
+func warningsFromKey(k *CKey) []ElektraWarning {
+	fields := map[string]map[string]string{}
+
+	for name, value := range k.MetaMap() {
+		if !strings.HasPrefix(name, "warnings/#") {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(name, "warnings/"), "/", 2)
+
+		if len(parts) != 2 {
+			continue
+		}
+
+		if fields[parts[0]] == nil {
+			fields[parts[0]] = map[string]string{}
+		}
+
+		fields[parts[0]][parts[1]] = value
+	}
+
+	// Elektra array indices sort correctly as strings, e.g. #9 < #_10
+	indices := make([]string, 0, len(fields))
+
+	for index := range fields {
+		indices = append(indices, index)
+	}
+
+	sort.Strings(indices)
+
+	warnings := make([]ElektraWarning, 0, len(indices))
+
+	for _, index := range indices {
+		f := fields[index]
+
+		warnings = append(warnings, ElektraWarning{problemFromFields(func(field string) string {
+			return f[field]
+		})})
+	}
+
+	return warnings
+}
+
+// parseLine converts the line meta value to an int, returning 0
+// if the value is missing or malformed.
+func parseLine(line string) int {
+	n, err := strconv.Atoi(line)
+
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// codeIs checks if the error code `number` or one of its
+// parent categories maps to `target`.
+func codeIs(number string, target error) bool {
+	for code := number; code != ""; code = errCodeParents[code] {
+		if err, ok := errCodeMap[code]; ok && err == target {
+			return true
+		}
+	}
+
+	return false
+}
+
 // error codes taken from libelektra/src/error/specification
 var (
+	ErrPermanent           = errors.New("C01000 - Permanent")
 	ErrResource            = errors.New("C01100 - Resource")
 	ErrOutOfMemory         = errors.New("C01110 - OutOfMemory")
 	ErrInstallation        = errors.New("C01200 - Installation")
+	ErrLogical             = errors.New("C01300 - Logical")
 	ErrInternal            = errors.New("C01310 - Internal")
 	ErrInterface           = errors.New("C01320 - Interface")
 	ErrPluginMisbehavior   = errors.New("C01330 - PluginMisbehavior")
 	ErrConflictingState    = errors.New("C02000 - ConflictingState")
+	ErrValidation          = errors.New("C03000 - Validation")
 	ErrValidationSyntactic = errors.New("C03100 - ValidationSyntactic")
 	ErrValidationSemantic  = errors.New("C03200 - ValidationSemantic")
 )
 
 var (
 	errCodeMap = map[string]error{
+		"C01000": ErrPermanent,
+		"C01100": ErrResource,
 		"C01110": ErrOutOfMemory,
 		"C01200": ErrInstallation,
+		"C01300": ErrLogical,
 		"C01310": ErrInternal,
 		"C01320": ErrInterface,
 		"C01330": ErrPluginMisbehavior,
 		"C02000": ErrConflictingState,
+		"C03000": ErrValidation,
 		"C03100": ErrValidationSyntactic,
 		"C03200": ErrValidationSemantic,
 	}
+
+	// errCodeParents maps every error code to the code of its category
+	errCodeParents = map[string]string{
+		"C01100": "C01000",
+		"C01110": "C01100",
+		"C01200": "C01000",
+		"C01300": "C01000",
+		"C01310": "C01300",
+		"C01320": "C01300",
+		"C01330": "C01300",
+		"C03100": "C03000",
+		"C03200": "C03000",
+	}
 )
@@ -0,0 +1,111 @@
+package kdb
+
+import (
+	"errors"
+	"testing"
+
+	. "go.libelektra.org/test"
+)
+
+func TestWarningsFromKey(t *testing.T) {
+	k, err := newKey("user:/tests/go/elektra/warnings")
+	Check(t, err, "could not create key")
+	defer k.Close()
+
+	metaKeys := map[string]string{
+		"warnings":                "#_10",
+		"warnings/#_10/number":    "C03200",
+		"warnings/#_10/reason":    "second",
+		"warnings/#0/number":      "C01330",
+		"warnings/#0/description": "Plugin Misbehavior",
+		"warnings/#0/reason":      "first",
+		"warnings/#0/module":      "dump",
+		"error/number":            "C01110",
+		"error/reason":            "not a warning",
+	}
+
+	for name, value := range metaKeys {
+		err := k.SetMeta(name, value)
+		Checkf(t, err, "could not set meta %q: %v", name, err)
+	}
+
+	warnings := warningsFromKey(k)
+	Assertf(t, len(warnings) == 2, "expected 2 warnings but got %d", len(warnings))
+
+	Assertf(t, warnings[0].Reason == "first", "first warning has reason %q", warnings[0].Reason)
+	Assertf(t, warnings[0].Module == "dump", "first warning has module %q", warnings[0].Module)
+	Assert(t, errors.Is(&warnings[0], ErrPluginMisbehavior), "first warning should be ErrPluginMisbehavior")
+
+	Assertf(t, warnings[1].Reason == "second", "second warning has reason %q", warnings[1].Reason)
+	Assert(t, errors.Is(&warnings[1], ErrValidationSemantic), "second warning should be ErrValidationSemantic")
+}
+
+var errCategoryTests = []struct {
+	number   string
+	category error
+	expected bool
+}{
+	{"C01110", ErrOutOfMemory, true},
+	{"C01110", ErrResource, true},
+	{"C01110", ErrPermanent, true},
+	{"C01100", ErrResource, true},
+	{"C01330", ErrLogical, true},
+	{"C01330", ErrResource, false},
+	{"C03100", ErrValidation, true},
+	{"C03100", ErrValidationSemantic, false},
+	{"C02000", ErrConflictingState, true},
+	{"C02000", ErrPermanent, false},
+}
+
+func TestErrorCategories(t *testing.T) {
+	for _, test := range errCategoryTests {
+		err := error(&ElektraError{Number: test.number, Err: errCodeMap[test.number]})
+
+		Assertf(t, errors.Is(err, test.category) == test.expected,
+			"errors.Is(%s, %v) should be %t", test.number, test.category, test.expected)
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	k, err := newKey("user:/tests/go/elektra/erroras")
+	Check(t, err, "could not create key")
+	defer k.Close()
+
+	_ = k.SetMeta("error/number", "C01110")
+	_ = k.SetMeta("error/line", "42")
+	_ = k.SetMeta("error/mountpoint", "user:/tests")
+	_ = k.SetMeta("error/configfile", "/tmp/tests.ecf")
+
+	err = errFromKey(k)
+
+	var resourceErr ResourceError
+	Assert(t, errors.As(err, &resourceErr), "error should be a ResourceError")
+	Assertf(t, resourceErr.Line == 42, "Line should be 42 but is %d", resourceErr.Line)
+	Assertf(t, resourceErr.Mountpoint == "user:/tests", "unexpected Mountpoint %q", resourceErr.Mountpoint)
+	Assertf(t, resourceErr.ConfigFile == "/tmp/tests.ecf", "unexpected ConfigFile %q", resourceErr.ConfigFile)
+
+	var validationErr ValidationError
+	Assert(t, !errors.As(err, &validationErr), "error should not be a ValidationError")
+}
+
+func TestNewElektraError(t *testing.T) {
+	err := NewElektraError(ErrValidationSemantic, "value out of range")
+
+	Assertf(t, err.Number == "C03200" && err.Description == "ValidationSemantic", "wrong number %q or description %q", err.Number, err.Description)
+	Assert(t, errors.Is(err, ErrValidation), "the error should belong to its category")
+}
+
+func TestArrayIndex(t *testing.T) {
+	for _, i := range []int{0, 9, 10, 123} {
+		n, err := ParseArrayIndex(ArrayIndex(i))
+		Check(t, err, "ParseArrayIndex failed")
+		Assertf(t, n == i, "%s should be parsed as %d but is %d", ArrayIndex(i), i, n)
+	}
+
+	Assert(t, ArrayIndex(10) == "#_10", "10 should be formatted as #_10")
+
+	for _, index := range []string{"", "#", "10", "#10", "#_1", "#-1"} {
+		_, err := ParseArrayIndex(index)
+		Assertf(t, err != nil, "%q should not be a valid array index", index)
+	}
+}
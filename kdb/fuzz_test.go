@@ -0,0 +1,209 @@
+package kdb_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	. "go.libelektra.org/test"
+)
+
+var fuzzNames = []string{
+	"user:/tests/go/elektra/fuzz",
+	"/tests/go/elektra/fuzz/#0",
+	"system:/tests/../go/./%/#_10",
+	`user:/a\/b/c\\d`,
+	"spec:/a/#/b",
+	"meta:/comment/#0",
+	"user:/",
+	"/",
+	"proc:/a",
+	"invalid",
+	"user:/a\x00b",
+}
+
+func addSeeds(f *testing.F) {
+	for _, name := range fuzzNames {
+		f.Add(name)
+	}
+}
+
+// FuzzNewKey checks that valid names survive a round trip.
+func FuzzNewKey(f *testing.F) {
+	addSeeds(f)
+
+	f.Fuzz(func(t *testing.T, name string) {
+		k, err := elektra.NewKey(name)
+
+		if err != nil {
+			return
+		}
+
+		defer k.Close()
+
+		k2, err := elektra.NewKey(k.Name())
+		Checkf(t, err, "canonical name %q of %q is invalid: %v", k.Name(), name, err)
+		defer k2.Close()
+
+		Assertf(t, k2.Name() == k.Name(), "name %q is not stable, got %q", k.Name(), k2.Name())
+		Assertf(t, k.Compare(k2) == 0, "Keys with the same name %q are not equal", k.Name())
+	})
+}
+
+// FuzzSetName checks that SetName either fails without modifying
+// the Key or results in the same name as NewKey.
+func FuzzSetName(f *testing.F) {
+	addSeeds(f)
+
+	f.Fuzz(func(t *testing.T, name string) {
+		const original = "user:/tests/go/elektra/fuzz/setname"
+
+		k, _ := elektra.NewKey(original)
+		defer k.Close()
+
+		if err := k.SetName(name); err != nil {
+			Assertf(t, k.Name() == original, "failed SetName(%q) modified the name to %q", name, k.Name())
+			return
+		}
+
+		k2, err := elektra.NewKey(name)
+		Checkf(t, err, "SetName accepted %q but NewKey did not: %v", name, err)
+		defer k2.Close()
+
+		Assertf(t, k.Name() == k2.Name(), "SetName(%q) = %q but NewKey = %q", name, k.Name(), k2.Name())
+	})
+}
+
+// FuzzCommonKeyName checks that the common name is a valid parent of both Keys.
+func FuzzCommonKeyName(f *testing.F) {
+	for _, name1 := range fuzzNames {
+		f.Add(name1, fuzzNames[0])
+	}
+
+	f.Fuzz(func(t *testing.T, name1, name2 string) {
+		k1, err1 := elektra.NewKey(name1)
+		k2, err2 := elektra.NewKey(name2)
+
+		if err1 != nil || err2 != nil {
+			return
+		}
+
+		common := elektra.CommonKeyName(k1, k2)
+
+		if common == "" {
+			return
+		}
+
+		parent, err := elektra.NewKey(common)
+		Checkf(t, err, "common name %q of %q and %q is invalid: %v", common, k1.Name(), k2.Name(), err)
+
+		Assertf(t, k1.IsBelowOrSame(parent), "%q is not below the common name %q", k1.Name(), common)
+		Assertf(t, k2.IsBelowOrSame(parent), "%q is not below the common name %q", k2.Name(), common)
+	})
+}
+
+// FuzzLookupByName checks that lookups find Keys by any of their names.
+func FuzzLookupByName(f *testing.F) {
+	addSeeds(f)
+
+	f.Fuzz(func(t *testing.T, name string) {
+		k, err := elektra.NewKey(name)
+
+		if err != nil {
+			Assert(t, elektra.NewKeySet().LookupByName(name) == nil, "lookup of an invalid name found a Key")
+			return
+		}
+
+		if k.Namespace() == elektra.KEY_NS_CASCADING || k.Namespace() == elektra.KEY_NS_META {
+			return
+		}
+
+		ks := elektra.NewKeySet(k)
+		defer ks.Close()
+
+		found := ks.LookupByName(name)
+		Assertf(t, found != nil && found.Name() == k.Name(), "lookup of %q did not find %q", name, k.Name())
+	})
+}
+
+func randomKeyName(r *rand.Rand) string {
+	namespaces := []string{"/", "spec:/", "proc:/", "dir:/", "user:/", "system:/", "default:/"}
+	parts := []string{"a", "b", "#0", "#_10", "#9", "%", `x\/y`, "aa", "a b"}
+
+	name := namespaces[r.Intn(len(namespaces))]
+
+	for i := r.Intn(4); i > 0; i-- {
+		name += parts[r.Intn(len(parts))]
+
+		if i > 1 {
+			name += "/"
+		}
+	}
+
+	return name
+}
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+
+	return 0
+}
+
+// TestCompareOrder checks that Compare is a total order that
+// is consistent with the order of the KeySet.
+func TestCompareOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	ks := elektra.NewKeySet()
+
+	for i := 0; i < 200; i++ {
+		k, err := elektra.NewKey(randomKeyName(r))
+		Check(t, err, "could not create Key")
+
+		ks.AppendKey(k)
+	}
+
+	keys := ks.ToSlice()
+
+	for i, a := range keys {
+		Assertf(t, a.Compare(a) == 0, "%q is not equal to itself", a.Name())
+
+		for j, b := range keys {
+			ab, ba := sign(a.Compare(b)), sign(b.Compare(a))
+			Assertf(t, ab == -ba, "Compare is not antisymmetric for %q and %q", a.Name(), b.Name())
+			Assertf(t, ab == sign(i-j), "Compare(%q, %q) = %d is inconsistent with the KeySet order", a.Name(), b.Name(), ab)
+		}
+	}
+}
+
+// TestNameRoundTrip checks that canonical names are stable.
+func TestNameRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 200; i++ {
+		name := randomKeyName(r)
+
+		t.Run(fmt.Sprintf("%q", name), func(t *testing.T) {
+			k, err := elektra.NewKey(name)
+			Check(t, err, "could not create Key")
+
+			k2, err := elektra.NewKey(k.Name())
+			Check(t, err, "could not create Key from canonical name")
+			Assertf(t, k2.Name() == k.Name(), "name %q is not stable, got %q", k.Name(), k2.Name())
+		})
+	}
+}
+
+func TestNulInName(t *testing.T) {
+	_, err := elektra.NewKey("user:/a\x00b")
+	Assert(t, err != nil, "NewKey should reject names containing NUL")
+
+	k, _ := elektra.NewKey("user:/a")
+	err = k.SetName("user:/a\x00b")
+	Assert(t, err != nil, "SetName should reject names containing NUL")
+}
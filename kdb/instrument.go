@@ -0,0 +1,129 @@
+package kdb
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Option configures a KDB created by New.
+type Option func(*KdbC)
+
+// WithLogger logs every Open, Get, Set and Close to `logger`. Successful
+// operations are logged at debug level, operations with warnings at warn
+// level and failed operations at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *KdbC) {
+		e.logger = logger
+	}
+}
+
+// WithTracer starts a Span of `tracer` for every Open, Get, Set and Close.
+func WithTracer(tracer Tracer) Option {
+	return func(e *KdbC) {
+		e.tracer = tracer
+	}
+}
+
+// Tracer creates Spans for KDB operations. It can be implemented with
+// OpenTelemetry or any other tracing library.
+type Tracer interface {
+	// Start starts a Span for `operation`, e.g. "kdb.Get", as child of the
+	// Span in `ctx` and returns the context that carries the new Span.
+	// The KDB methods do not take a context yet, so `ctx` is currently
+	// always context.Background().
+	Start(ctx context.Context, operation string) (context.Context, Span)
+}
+
+// Span is a single traced KDB operation.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	// End finishes the Span, `err` is nil if the operation succeeded.
+	End(err error)
+}
+
+// operation measures a single KDB operation, a nil operation does nothing.
+type operation struct {
+	e         *KdbC
+	ctx       context.Context
+	name      string
+	parentKey string
+	start     time.Time
+	span      Span
+}
+
+func (e *KdbC) startOperation(name string, parentKey Key) *operation {
+	if e.logger == nil && e.tracer == nil {
+		return nil
+	}
+
+	op := &operation{e: e, ctx: context.Background(), name: "kdb." + name, start: time.Now()}
+
+	if parentKey != nil {
+		op.parentKey = parentKey.Name()
+	}
+
+	if e.tracer != nil {
+		op.ctx, op.span = e.tracer.Start(op.ctx, op.name)
+	}
+
+	return op
+}
+
+// end records the result, `keySet` is nil for Open and Close.
+func (op *operation) end(keySet KeySet, changed bool, warnings []ElektraWarning, err error) {
+	if op == nil {
+		return
+	}
+
+	attrs := []slog.Attr{slog.Duration("duration", time.Since(op.start))}
+
+	if op.parentKey != "" {
+		attrs = append(attrs, slog.String("parentKey", op.parentKey))
+	}
+
+	if keySet != nil {
+		attrs = append(attrs, slog.Int("keys", keySet.Len()), slog.Bool("changed", changed))
+	}
+
+	if len(warnings) > 0 {
+		numbers := make([]string, len(warnings))
+
+		for i, w := range warnings {
+			numbers[i] = w.Number
+		}
+
+		attrs = append(attrs, slog.Any("warnings", numbers))
+	}
+
+	var elektraErr *ElektraError
+
+	if errors.As(err, &elektraErr) {
+		attrs = append(attrs, slog.String("errorNumber", elektraErr.Number))
+	}
+
+	if op.span != nil {
+		for _, attr := range attrs {
+			op.span.SetAttribute(attr.Key, attr.Value.Any())
+		}
+
+		op.span.End(err)
+	}
+
+	if op.e.logger == nil {
+		return
+	}
+
+	level := slog.LevelDebug
+
+	switch {
+	case err != nil:
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", err.Error()))
+	case len(warnings) > 0:
+		level = slog.LevelWarn
+	}
+
+	op.e.logger.LogAttrs(op.ctx, level, op.name, attrs...)
+}
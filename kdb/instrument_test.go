@@ -0,0 +1,63 @@
+package kdb_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	. "go.libelektra.org/test"
+)
+
+type testSpan struct {
+	attributes map[string]interface{}
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *testSpan) End(err error) {
+	s.ended = true
+}
+
+type testTracer struct {
+	spans map[string]*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, operation string) (context.Context, elektra.Span) {
+	span := &testSpan{attributes: map[string]interface{}{}}
+	t.spans[operation] = span
+
+	return ctx, span
+}
+
+func TestInstrumentation(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tracer := &testTracer{spans: map[string]*testSpan{}}
+
+	kdb := elektra.New(elektra.WithLogger(logger), elektra.WithTracer(tracer))
+
+	err := kdb.Open()
+	Check(t, err, "could not open KDB")
+	defer kdb.Close()
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/elektra/instrument")
+	_, err = kdb.Get(elektra.NewKeySet(), parentKey)
+	Check(t, err, "could not Get")
+
+	log := buf.String()
+	Assertf(t, strings.Contains(log, "msg=kdb.Get") && strings.Contains(log, "parentKey="+parentKey.Name()), "Get was not logged:\n%s", log)
+	Assertf(t, strings.Contains(log, "msg=kdb.Open"), "Open was not logged:\n%s", log)
+
+	span := tracer.spans["kdb.Get"]
+	Assert(t, span != nil && span.ended, "Get span was not ended")
+	Assert(t, span.attributes["parentKey"] == parentKey.Name(), "Get span is missing the parent Key")
+	_, hasKeys := span.attributes["keys"]
+	Assert(t, hasKeys, "Get span is missing the number of Keys")
+}
@@ -6,6 +6,8 @@ import "C"
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 )
 
 // KDB (key data base) access functions
@@ -19,48 +21,103 @@ type KDB interface {
 	Version() (string, error)
 }
 
+// WarningsKDB is implemented by KDBs that report the warnings Elektra
+// attached to the parent Key of Get and Set. KdbC implements it, wrappers
+// of a KDB should implement it as well and forward it to the KDB they wrap.
+type WarningsKDB interface {
+	KDB
+
+	GetWithWarnings(keySet KeySet, parentKey Key) (changed bool, warnings []ElektraWarning, err error)
+	SetWithWarnings(keySet KeySet, parentKey Key) (changed bool, warnings []ElektraWarning, err error)
+}
+
+// GetWithWarnings calls GetWithWarnings if `kdb` implements WarningsKDB
+// and falls back to Get without warnings otherwise.
+func GetWithWarnings(kdb KDB, keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
+	if w, ok := kdb.(WarningsKDB); ok {
+		return w.GetWithWarnings(keySet, parentKey)
+	}
+
+	changed, err := kdb.Get(keySet, parentKey)
+
+	return changed, nil, err
+}
+
+// SetWithWarnings calls SetWithWarnings if `kdb` implements WarningsKDB
+// and falls back to Set without warnings otherwise.
+func SetWithWarnings(kdb KDB, keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
+	if w, ok := kdb.(WarningsKDB); ok {
+		return w.SetWithWarnings(keySet, parentKey)
+	}
+
+	changed, err := kdb.Set(keySet, parentKey)
+
+	return changed, nil, err
+}
+
+var _ WarningsKDB = (*KdbC)(nil)
+
 type KdbC struct {
 	handle *C.struct__KDB
+	logger *slog.Logger
+	tracer Tracer
+
+	sandbox   []Key
+	sandboxed bool
 }
 
 // New returns a new KDB instance.
-func New() KDB {
-	return &KdbC{}
+func New(options ...Option) KDB {
+	e := &KdbC{}
+
+	for _, option := range options {
+		option(e)
+	}
+
+	return e
 }
 
 // Open creates a handle to the kdb library,
 // this is mandatory to Get / Set Keys.
 func (e *KdbC) Open() error {
-	key, err := newKey("/")
+	op := e.startOperation("Open", nil)
+	err := e.open(nil)
+	op.end(nil, false, nil, err)
 
-	if err != nil {
-		return err
-	}
+	return err
+}
 
-	handle := C.kdbOpen(nil, key.Ptr)
+// Open creates a handle to the kdb library,
+// this is mandatory to Get / Set Keys.
+// This function also enforces a contract.
+func (e *KdbC) OpenWithContract(contract KeySet) error {
+	cContract, err := toCKeySet(contract)
 
-	if handle == nil {
-		return errFromKey(key)
+	if err != nil {
+		return err
 	}
 
-	e.handle = handle
+	op := e.startOperation("Open", nil)
+	err = e.open(cContract)
+	op.end(nil, false, nil, err)
 
-	return nil
+	return err
 }
 
-// Open creates a handle to the kdb library,
-// this is mandatory to Get / Set Keys.
-// This function also enforces a contract.
-func (e *KdbC) OpenWithContract(contract KeySet) error {
+func (e *KdbC) open(contract *CKeySet) error {
 	key, err := newKey("/")
 
 	if err != nil {
 		return err
 	}
 
-	cContract, err := toCKeySet(contract)
+	var cContract *C.struct__KeySet
+
+	if contract != nil {
+		cContract = contract.Ptr
+	}
 
-	handle := C.kdbOpen(cContract.Ptr, key.Ptr)
+	handle := C.kdbOpen(cContract, key.Ptr)
 
 	if handle == nil {
 		return errFromKey(key)
@@ -73,6 +130,14 @@ func (e *KdbC) OpenWithContract(contract KeySet) error {
 
 // Close closes the kdb handle.
 func (e *KdbC) Close() error {
+	op := e.startOperation("Close", nil)
+	err := e.close()
+	op.end(nil, false, nil, err)
+
+	return err
+}
+
+func (e *KdbC) close() error {
 	key, err := newKey("/")
 
 	if err != nil {
@@ -92,69 +157,121 @@ func (e *KdbC) Close() error {
 // Returns true if Keys have been loaded or updated and an
 // error if something went wrong.
 func (e *KdbC) Get(keySet KeySet, parentKey Key) (bool, error) {
+	changed, _, err := e.GetWithWarnings(keySet, parentKey)
+
+	return changed, err
+}
+
+// GetWithWarnings works like Get but additionally returns the
+// warnings Elektra attached to the parentKey. Use the package level
+// GetWithWarnings to get the warnings through wrappers of a KDB.
+func (e *KdbC) GetWithWarnings(keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
+	op := e.startOperation("Get", parentKey)
+	changed, warnings, err := e.get(keySet, parentKey)
+	op.end(keySet, changed, warnings, err)
+
+	return changed, warnings, err
+}
+
+func (e *KdbC) get(keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
 	cKey, err := toCKey(parentKey)
 
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 
 	cKeySet, err := toCKeySet(keySet)
 
 	if err != nil {
-		return false, err
+		return false, nil, err
+	}
+
+	if cKeySet.frozen {
+		return false, nil, ErrKeySetFrozen
 	}
 
 	changed := C.kdbGet(e.handle, cKeySet.Ptr, cKey.Ptr)
+	warnings := warningsFromKey(cKey)
 
 	if changed == -1 {
-		return false, errFromKey(cKey)
+		return false, warnings, errFromKey(cKey)
 	}
 
-	return changed == 1, nil
+	return changed == 1, warnings, nil
 }
 
 // Set sets all Keys of a KeySet.
 // Returns true if any of the keys have changed and an error if
 // something happened (such as a conflict).
 func (e *KdbC) Set(keySet KeySet, parentKey Key) (bool, error) {
+	changed, _, err := e.SetWithWarnings(keySet, parentKey)
+
+	return changed, err
+}
+
+// SetWithWarnings works like Set but additionally returns the
+// warnings Elektra attached to the parentKey, see GetWithWarnings.
+func (e *KdbC) SetWithWarnings(keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
+	op := e.startOperation("Set", parentKey)
+	changed, warnings, err := e.set(keySet, parentKey)
+	op.end(keySet, changed, warnings, err)
+
+	return changed, warnings, err
+}
+
+func (e *KdbC) set(keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
 	cKey, err := toCKey(parentKey)
 
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 
 	cKeySet, err := toCKeySet(keySet)
 
 	if err != nil {
-		return false, err
+		return false, nil, err
+	}
+
+	if err = e.checkSandbox(cKeySet, cKey); err != nil {
+		return false, nil, err
 	}
 
 	changed := C.kdbSet(e.handle, cKeySet.Ptr, cKey.Ptr)
+	warnings := warningsFromKey(cKey)
 
 	if changed == -1 {
-		return false, errFromKey(cKey)
+		return false, warnings, errFromKey(cKey)
 	}
 
-	return changed == 1, nil
+	return changed == 1, warnings, nil
 }
 
 // Version `Get`s the current version of Elektra from
 // the "system:/elektra/version/constants/KDB_VERSION" key
-// in the format Major.Minor.Micro, be aware that this can
-// lead to unexpected state-changes.
+// in the format Major.Minor.Micro. Only the Keys below
+// "system:/elektra/version" are loaded, use LibraryVersion
+// for the version this package was compiled against.
 func (e *KdbC) Version() (string, error) {
-	k, err := NewKey("system:/elektra/version")
+	k, err := newKey("system:/elektra/version")
 
 	if err != nil {
 		return "", err
 	}
 
+	defer k.Close()
+
 	ks := NewKeySet()
+	defer ks.Close()
 
-	_, err = e.Get(ks, k)
+	if _, err = e.Get(ks, k); err != nil {
+		return "", err
+	}
 
 	versionKey := ks.LookupByName("system:/elektra/version/constants/KDB_VERSION")
-	version := versionKey.String()
 
-	return version, nil
+	if versionKey == nil {
+		return "", fmt.Errorf("%w: system:/elektra/version/constants/KDB_VERSION", ErrKeyNotFound)
+	}
+
+	return versionKey.String(), nil
 }
@@ -140,3 +140,28 @@ func TestVersion(t *testing.T) {
 	Checkf(t, err, "kdb.Version() failed: %v", err)
 	Assert(t, version != "", "kdb.Version() is empty")
 }
+
+type plainKDB struct {
+	elektra.KDB
+}
+
+func TestGetWithWarnings(t *testing.T) {
+	kdb := elektra.New()
+
+	err := kdb.Open()
+	defer kdb.Close()
+
+	Checkf(t, err, "kdb.Open() failed: %v", err)
+
+	_, isWarningsKDB := kdb.(elektra.WarningsKDB)
+	Assert(t, isWarningsKDB, "the KDB of New should implement WarningsKDB")
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/elektra/warnings")
+
+	_, _, err = elektra.GetWithWarnings(kdb, elektra.NewKeySet(), parentKey)
+	Checkf(t, err, "GetWithWarnings failed: %v", err)
+
+	_, warnings, err := elektra.GetWithWarnings(plainKDB{kdb}, elektra.NewKeySet(), parentKey)
+	Checkf(t, err, "GetWithWarnings without WarningsKDB failed: %v", err)
+	Assertf(t, warnings == nil, "a KDB without WarningsKDB should not report warnings: %v", warnings)
+}
@@ -0,0 +1,122 @@
+package kdbtest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// KeySetFromMap creates a KeySet from a map of Key names to values.
+// It panics if a name is invalid, since the map is part of the test.
+func KeySetFromMap(keys map[string]string) elektra.KeySet {
+	ks := elektra.NewKeySet()
+
+	for name, value := range keys {
+		key, err := elektra.NewKey(name, value)
+
+		if err != nil {
+			panic(fmt.Sprintf("kdbtest: invalid key name %q: %v", name, err))
+		}
+
+		ks.AppendKey(key)
+	}
+
+	return ks
+}
+
+// AssertKeySetEqual fails the test if the KeySets differ in names,
+// values or meta Keys and prints every difference.
+func AssertKeySetEqual(t testing.TB, want, got elektra.KeySet) {
+	t.Helper()
+
+	if diff := Diff(want, got); len(diff) > 0 {
+		t.Fatalf("KeySets differ (-want +got):\n%s", strings.Join(diff, "\n"))
+	}
+}
+
+// Diff returns the differences between the KeySets, one line per difference:
+// "- name" for missing Keys, "+ name" for unexpected Keys and "~ name" for
+// Keys with a different value or different meta Keys.
+func Diff(want, got elektra.KeySet) []string {
+	var diff []string
+
+	wantKeys, gotKeys := want.ToSlice(), got.ToSlice()
+	i, j := 0, 0
+
+	for i < len(wantKeys) || j < len(gotKeys) {
+		var cmp int
+
+		switch {
+		case i == len(wantKeys):
+			cmp = 1
+		case j == len(gotKeys):
+			cmp = -1
+		default:
+			cmp = wantKeys[i].Compare(gotKeys[j])
+		}
+
+		switch {
+		case cmp < 0:
+			diff = append(diff, fmt.Sprintf("- %s = %q", wantKeys[i].Name(), wantKeys[i].String()))
+			i++
+		case cmp > 0:
+			diff = append(diff, fmt.Sprintf("+ %s = %q", gotKeys[j].Name(), gotKeys[j].String()))
+			j++
+		default:
+			diff = append(diff, keyDiff(wantKeys[i], gotKeys[j])...)
+			i++
+			j++
+		}
+	}
+
+	return diff
+}
+
+func keyDiff(want, got elektra.Key) []string {
+	var diff []string
+
+	name := want.Name()
+
+	if want.String() != got.String() || !bytes.Equal(want.Bytes(), got.Bytes()) {
+		diff = append(diff, fmt.Sprintf("~ %s: value %q != %q", name, want.String(), got.String()))
+	}
+
+	wantMeta, gotMeta := want.MetaMap(), got.MetaMap()
+	names := map[string]bool{}
+
+	for n := range wantMeta {
+		names[n] = true
+	}
+
+	for n := range gotMeta {
+		names[n] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+
+	for n := range names {
+		sorted = append(sorted, n)
+	}
+
+	sort.Strings(sorted)
+
+	for _, n := range sorted {
+		wantValue, inWant := wantMeta[n]
+		gotValue, inGot := gotMeta[n]
+
+		switch {
+		case !inGot:
+			diff = append(diff, fmt.Sprintf("~ %s: missing meta %s = %q", name, n, wantValue))
+		case !inWant:
+			diff = append(diff, fmt.Sprintf("~ %s: unexpected meta %s = %q", name, n, gotValue))
+		case wantValue != gotValue:
+			diff = append(diff, fmt.Sprintf("~ %s: meta %s %q != %q", name, n, wantValue, gotValue))
+		}
+	}
+
+	return diff
+}
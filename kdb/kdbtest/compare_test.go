@@ -0,0 +1,76 @@
+package kdbtest_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.libelektra.org/kdb/kdbtest"
+	. "go.libelektra.org/test"
+)
+
+func TestDiff(t *testing.T) {
+	want := kdbtest.KeySetFromMap(map[string]string{
+		"user:/tests/go/diff/same":    "1",
+		"user:/tests/go/diff/changed": "old",
+		"user:/tests/go/diff/missing": "",
+	})
+	got := kdbtest.KeySetFromMap(map[string]string{
+		"user:/tests/go/diff/same":       "1",
+		"user:/tests/go/diff/changed":    "new",
+		"user:/tests/go/diff/unexpected": "",
+	})
+
+	_ = got.LookupByName("user:/tests/go/diff/same").SetMeta("type", "long")
+
+	diff := kdbtest.Diff(want, got)
+	expected := []string{
+		`~ user:/tests/go/diff/changed: value "old" != "new"`,
+		`- user:/tests/go/diff/missing = ""`,
+		`~ user:/tests/go/diff/same: unexpected meta type = "long"`,
+		`+ user:/tests/go/diff/unexpected = ""`,
+	}
+
+	Assertf(t, strings.Join(diff, "\n") == strings.Join(expected, "\n"), "unexpected diff:\n%s", strings.Join(diff, "\n"))
+	Assert(t, len(kdbtest.Diff(want, want)) == 0, "a KeySet should not differ from itself")
+}
+
+func TestDump(t *testing.T) {
+	ks := kdbtest.KeySetFromMap(map[string]string{
+		"user:/tests/go/dump/a": "multi\nline",
+		"user:/tests/go/dump/b": "",
+	})
+	_ = ks.LookupByName("user:/tests/go/dump/a").SetMeta("comment/#0", " hello")
+
+	binary := []byte{0, 1, '\n', 255}
+	_ = ks.LookupByName("user:/tests/go/dump/b").SetBytes(binary)
+
+	var buf bytes.Buffer
+	err := kdbtest.WriteDumpV1(&buf, ks)
+	Check(t, err, "WriteDumpV1 failed")
+
+	read, err := kdbtest.ReadDumpV1(&buf)
+	Check(t, err, "ReadDumpV1 failed")
+	kdbtest.AssertKeySetEqual(t, ks, read)
+	Assertf(t, bytes.Equal(read.LookupByName("user:/tests/go/dump/b").Bytes(), binary), "binary value was not preserved: %v", read.LookupByName("user:/tests/go/dump/b").Bytes())
+
+	_, err = kdbtest.ReadDumpV1(strings.NewReader("kdbOpen 2\n$end\n"))
+	Assert(t, errors.Is(err, kdbtest.ErrInvalidDump), "an unsupported version should return ErrInvalidDump")
+
+	_, err = kdbtest.ReadDumpV1(strings.NewReader("kdbOpen 1\n$key string 100 0\nshort\n"))
+	Assert(t, errors.Is(err, kdbtest.ErrInvalidDump), "a truncated dump should return ErrInvalidDump")
+
+	_, err = kdbtest.ReadDumpV1(strings.NewReader("kdbOpen 1\n$key string 9223372036854775807 1\nshort\n"))
+	Assert(t, errors.Is(err, kdbtest.ErrInvalidDump), "overflowing sizes should return ErrInvalidDump")
+}
+
+func TestGolden(t *testing.T) {
+	ks := kdbtest.KeySetFromMap(map[string]string{
+		"user:/tests/go/golden/host": "localhost",
+		"user:/tests/go/golden/port": "8080",
+	})
+	_ = ks.LookupByName("user:/tests/go/golden/host").SetMeta("type", "string")
+
+	kdbtest.Golden(t, "testdata/golden.dump", ks)
+}
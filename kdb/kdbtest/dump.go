@@ -0,0 +1,193 @@
+package kdbtest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+var ErrInvalidDump = errors.New("invalid dump")
+
+// KeySetFromDumpV1 reads a file in the format version 1 of Elektra's dump
+// plugin with absolute Key names, see ReadDumpV1.
+func KeySetFromDumpV1(path string) (elektra.KeySet, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return ReadDumpV1(f)
+}
+
+// ReadDumpV1 reads a KeySet in the format version 1 of Elektra's dump plugin,
+// the header has to be `kdbOpen 1`. Files written by the dump plugin of
+// current libelektra releases start with `kdbOpen 2` and are not supported,
+// like other versions they return ErrInvalidDump.
+func ReadDumpV1(r io.Reader) (elektra.KeySet, error) {
+	br := bufio.NewReader(r)
+
+	header, err := br.ReadString('\n')
+
+	if err != nil || header != "kdbOpen 1\n" {
+		return nil, fmt.Errorf("%w: missing kdbOpen 1 header, only version 1 is supported", ErrInvalidDump)
+	}
+
+	ks := elektra.NewKeySet()
+
+	if err = readDumpKeys(br, ks); err != nil {
+		ks.Close()
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// readDumpKeys reads the commands after the header into `ks`. Keys are
+// appended right after they are created, so closing `ks` frees all of them.
+func readDumpKeys(br *bufio.Reader, ks elektra.KeySet) error {
+	var current elektra.Key
+
+	for {
+		line, err := br.ReadString('\n')
+
+		if err == io.EOF && line == "" {
+			return fmt.Errorf("%w: missing $end", ErrInvalidDump)
+		} else if err != nil && err != io.EOF {
+			return err
+		}
+
+		fields := strings.Fields(line)
+
+		if len(fields) == 0 {
+			return fmt.Errorf("%w: empty line", ErrInvalidDump)
+		}
+
+		switch fields[0] {
+		case "$end":
+			return nil
+		case "$key":
+			if len(fields) != 4 {
+				return fmt.Errorf("%w: %q", ErrInvalidDump, line)
+			}
+
+			name, value, err := readPair(br, fields[2], fields[3])
+
+			if err != nil {
+				return err
+			}
+
+			if current, err = elektra.NewKey(name); err != nil {
+				return fmt.Errorf("%w: invalid key name %q", ErrInvalidDump, name)
+			}
+
+			ks.AppendKey(current)
+
+			if fields[1] == "binary" {
+				err = current.SetBytes([]byte(value))
+			} else {
+				err = current.SetString(value)
+			}
+
+			if err != nil {
+				return err
+			}
+		case "$meta", "$copymeta":
+			if len(fields) != 3 || current == nil {
+				return fmt.Errorf("%w: %q", ErrInvalidDump, line)
+			}
+
+			first, second, err := readPair(br, fields[1], fields[2])
+
+			if err != nil {
+				return err
+			}
+
+			if fields[0] == "$meta" {
+				err = current.SetMeta(first, second)
+			} else if source := ks.LookupByName(first); source != nil {
+				err = current.CopyMeta(source, second)
+			} else {
+				err = fmt.Errorf("%w: unknown key %q in $copymeta", ErrInvalidDump, first)
+			}
+
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: unknown command %q", ErrInvalidDump, fields[0])
+		}
+	}
+}
+
+// readPair reads two strings of the given sizes followed by a newline. The
+// sizes are not trusted, the data is read in chunks, so a wrong size fails
+// at the end of the input instead of allocating the size up front.
+func readPair(r *bufio.Reader, firstSize, secondSize string) (string, string, error) {
+	n1, err1 := strconv.ParseInt(firstSize, 10, 64)
+	n2, err2 := strconv.ParseInt(secondSize, 10, 64)
+
+	if err1 != nil || err2 != nil || n1 < 0 || n2 < 0 || n1 > math.MaxInt64-n2-1 {
+		return "", "", fmt.Errorf("%w: invalid sizes %s %s", ErrInvalidDump, firstSize, secondSize)
+	}
+
+	var buf bytes.Buffer
+
+	if _, err := io.CopyN(&buf, r, n1+n2+1); err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidDump, err)
+	}
+
+	data := buf.Bytes()
+
+	if data[n1+n2] != '\n' {
+		return "", "", fmt.Errorf("%w: missing newline", ErrInvalidDump)
+	}
+
+	return string(data[:n1]), string(data[n1 : n1+n2]), nil
+}
+
+// WriteDumpV1 writes the KeySet in the format version 1 of Elektra's dump
+// plugin, binary Keys are written as `$key binary`.
+func WriteDumpV1(w io.Writer, ks elektra.KeySet) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "kdbOpen 1")
+
+	for _, k := range ks.ToSlice() {
+		name, kind, value := k.Name(), "string", k.String()
+
+		if k.HasMeta("binary") {
+			kind, value = "binary", string(k.Bytes())
+		}
+
+		fmt.Fprintf(bw, "$key %s %d %d\n%s%s\n", kind, len(name), len(value), name, value)
+
+		meta := k.MetaMap()
+		names := make([]string, 0, len(meta))
+
+		for n := range meta {
+			names = append(names, n)
+		}
+
+		sort.Strings(names)
+
+		for _, n := range names {
+			fmt.Fprintf(bw, "$meta %d %d\n%s%s\n", len(n), len(meta[n]), n, meta[n])
+		}
+	}
+
+	fmt.Fprintln(bw, "$end")
+
+	return bw.Flush()
+}
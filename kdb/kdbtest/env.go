@@ -0,0 +1,181 @@
+// Package kdbtest provides utilities for tests that use the key database.
+//
+// The helpers live here instead of in go.libelektra.org/test because
+// that package is imported by the internal tests of the kdb package and
+// therefore can not import kdb itself.
+//
+// Only the user:/ namespace is isolated: libelektra has no way to relocate
+// the system:/ namespace at runtime, so Mount and Snapshot of system:/ Keys
+// modify the configuration of the machine. Isolate changes environment
+// variables and panics in parallel tests. Parallel tests should rely on Main
+// and use distinct Key names and mountpoints, e.g. below t.Name().
+package kdbtest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/mount"
+)
+
+// homeVariables are the variables the resolver uses to locate
+// the user:/ configuration and the cache.
+var homeVariables = []string{"HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME"}
+
+// Main runs the tests of a package with a temporary home directory,
+// so tests never read or write the user:/ configuration of the developer.
+// Call it from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		kdbtest.Main(m)
+//	}
+func Main(m *testing.M) {
+	home, err := os.MkdirTemp("", "go-elektra-test-")
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, name := range homeVariables {
+		os.Setenv(name, homeDir(home, name))
+	}
+
+	code := m.Run()
+
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// Isolate gives the test its own temporary home directory which is
+// removed when the test ends. Like t.Setenv it must not be used in
+// parallel tests, use Main and separate mountpoints for those.
+func Isolate(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+
+	for _, name := range homeVariables {
+		t.Setenv(name, homeDir(home, name))
+	}
+
+	return home
+}
+
+func homeDir(home, variable string) string {
+	switch variable {
+	case "XDG_CONFIG_HOME":
+		return filepath.Join(home, ".config")
+	case "XDG_CACHE_HOME":
+		return filepath.Join(home, ".cache")
+	}
+
+	return home
+}
+
+// Open opens a KDB handle that is closed when the test ends.
+func Open(t testing.TB) elektra.KDB {
+	t.Helper()
+
+	handle := elektra.New()
+
+	if err := handle.Open(); err != nil {
+		t.Fatalf("could not open KDB: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = handle.Close()
+	})
+
+	return handle
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// Mount mounts `mp` for the duration of the test. If no path is set the
+// backend is stored in a file in t.TempDir(), the resolver defaults to
+// "resolver" and the storage to "dump". The mountpoint is added to the
+// system:/elektra/mountpoints of the machine and removed when the test
+// ends, so mounting requires write access to it and parallel tests have
+// to use different mountpoint names.
+func Mount(t testing.TB, handle elektra.KDB, mp mount.Mountpoint) mount.Mountpoint {
+	t.Helper()
+
+	if mp.Path == "" {
+		mp.Path = filepath.Join(t.TempDir(), unsafeChars.ReplaceAllString(t.Name(), "_")+".ecf")
+	}
+
+	if mp.Resolver.Name == "" {
+		mp.Resolver.Name = "resolver"
+	}
+
+	if mp.Storage.Name == "" {
+		mp.Storage.Name = "dump"
+	}
+
+	if err := mount.Mount(handle, mp); err != nil {
+		t.Fatalf("could not mount %q: %v", mp.Name, err)
+	}
+
+	t.Cleanup(func() {
+		if err := mount.Umount(handle, mp.Name); err != nil {
+			t.Errorf("could not unmount %q: %v", mp.Name, err)
+		}
+	})
+
+	return mp
+}
+
+// Snapshot saves `name` and all Keys below it and restores
+// them when the test ends, Keys added by the test are removed.
+func Snapshot(t testing.TB, handle elektra.KDB, name string) {
+	t.Helper()
+
+	parentKey, err := elektra.NewKey(name)
+
+	if err != nil {
+		t.Fatalf("invalid key name %q: %v", name, err)
+	}
+
+	ks := elektra.NewKeySet()
+
+	if _, err = handle.Get(ks, parentKey); err != nil {
+		ks.Close()
+		parentKey.Close()
+		t.Fatalf("could not get %q: %v", name, err)
+	}
+
+	snapshot := ks.Below(parentKey)
+	ks.Close()
+
+	t.Cleanup(func() {
+		defer parentKey.Close()
+		defer snapshot.Close()
+
+		if err := restore(handle, parentKey, snapshot); err != nil {
+			t.Errorf("could not restore %q: %v", name, err)
+		}
+	})
+}
+
+func restore(handle elektra.KDB, parentKey elektra.Key, snapshot elektra.KeySet) error {
+	ks := elektra.NewKeySet()
+	defer ks.Close()
+
+	if _, err := handle.Get(ks, parentKey); err != nil {
+		return err
+	}
+
+	if err := elektra.RemoveTree(ks, parentKey); err != nil && !errors.Is(err, elektra.ErrKeyNotFound) {
+		return err
+	}
+
+	ks.Append(snapshot)
+
+	_, err := handle.Set(ks, parentKey)
+
+	return err
+}
@@ -0,0 +1,46 @@
+package kdbtest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/kdb/kdbtest"
+	. "go.libelektra.org/test"
+)
+
+func TestIsolate(t *testing.T) {
+	home := kdbtest.Isolate(t)
+
+	Assertf(t, os.Getenv("HOME") == home, "HOME should be %q but is %q", home, os.Getenv("HOME"))
+	Assert(t, os.Getenv("XDG_CONFIG_HOME") == filepath.Join(home, ".config"), "XDG_CONFIG_HOME was not set")
+}
+
+func TestSnapshot(t *testing.T) {
+	kdbtest.Isolate(t)
+	handle := kdbtest.Open(t)
+	name := "user:/tests/go/elektra/kdbtest/snapshot"
+
+	t.Run("modify", func(t *testing.T) {
+		kdbtest.Snapshot(t, handle, name)
+
+		ks := elektra.NewKeySet()
+		parentKey, _ := elektra.NewKey(name)
+		_, err := handle.Get(ks, parentKey)
+		Check(t, err, "could not get")
+
+		key, _ := elektra.NewKey(name+"/added", "value")
+		ks.AppendKey(key)
+
+		_, err = handle.Set(ks, parentKey)
+		Check(t, err, "could not set")
+	})
+
+	ks := elektra.NewKeySet()
+	parentKey, _ := elektra.NewKey(name)
+	_, err := handle.Get(ks, parentKey)
+	Check(t, err, "could not get")
+
+	Assert(t, ks.LookupByName(name+"/added") == nil, "Snapshot did not remove the added Key")
+}
@@ -0,0 +1,49 @@
+package kdbtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+var update = flag.Bool("kdbtest.update", false, "update the golden files of kdbtest.Golden")
+
+// Golden compares `got` with the KeySet stored in the dump file `path`,
+// see ReadDumpV1. Run the tests with -kdbtest.update to write `got` to
+// the file instead.
+func Golden(t testing.TB, path string, got elektra.KeySet) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("could not create directory for golden file: %v", err)
+		}
+
+		f, err := os.Create(path)
+
+		if err != nil {
+			t.Fatalf("could not create golden file: %v", err)
+		}
+
+		defer f.Close()
+
+		if err = WriteDumpV1(f, got); err != nil {
+			t.Fatalf("could not write golden file: %v", err)
+		}
+
+		return
+	}
+
+	want, err := KeySetFromDumpV1(path)
+
+	if err != nil {
+		t.Fatalf("could not read golden file %s (run with -kdbtest.update to create it): %v", path, err)
+	}
+
+	defer want.Close()
+
+	AssertKeySetEqual(t, want, got)
+}
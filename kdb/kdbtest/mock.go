@@ -0,0 +1,270 @@
+package kdbtest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// Call is a call of a method of Mock.
+type Call struct {
+	// Method is "Open", "Close", "Get", "Set" or "Version".
+	Method string
+	// ParentKey is the name of the parent Key of Get and Set.
+	ParentKey string
+}
+
+type fault struct {
+	method string
+	n      int
+	err    error
+}
+
+type expectation struct {
+	method, parentKey string
+}
+
+// Mock is a KDB that stores its Keys in memory. Errors and latency can be
+// injected to test error paths that are hard to produce with a real backend.
+// It is safe for concurrent use.
+type Mock struct {
+	// Data holds the Keys of the mock, Get copies them to the
+	// KeySet of the caller and Set replaces them.
+	Data elektra.KeySet
+	// Latency delays every call.
+	Latency time.Duration
+	// VersionString is returned by Version.
+	VersionString string
+
+	mu           sync.Mutex
+	calls        []Call
+	counts       map[string]int
+	faults       []fault
+	warnings     map[string][]elektra.ElektraWarning
+	expectations []expectation
+}
+
+var _ elektra.WarningsKDB = (*Mock)(nil)
+
+// NewMock creates an empty Mock, the expectations registered with
+// Expect are checked when the test ends.
+func NewMock(t testing.TB) *Mock {
+	m := &Mock{
+		Data:          elektra.NewKeySet(),
+		VersionString: "0.9.0",
+		counts:        map[string]int{},
+		warnings:      map[string][]elektra.ElektraWarning{},
+	}
+
+	t.Cleanup(func() {
+		t.Helper()
+		m.AssertExpectations(t)
+	})
+
+	return m
+}
+
+// Error creates an ElektraError with the error code of `code`,
+// e.g. Error(elektra.ErrConflictingState, "key was modified").
+func Error(code error, reason string) *elektra.ElektraError {
+	err := elektra.NewElektraError(code, reason)
+	err.Module = "kdbtest"
+
+	return err
+}
+
+// Fail makes the `n`th call (starting at 1) of `method` return `err`,
+// if `n` is 0 every call fails.
+func (m *Mock) Fail(method string, n int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.faults = append(m.faults, fault{method, n, err})
+}
+
+// Warn makes every call of `method`, "Get" or "Set", report `warnings`
+// through GetWithWarnings and SetWithWarnings.
+func (m *Mock) Warn(method string, warnings ...elektra.ElektraWarning) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.warnings[method] = append(m.warnings[method], warnings...)
+}
+
+// warningsOf returns the warnings registered with Warn for `method`.
+func (m *Mock) warningsOf(method string) []elektra.ElektraWarning {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]elektra.ElektraWarning(nil), m.warnings[method]...)
+}
+
+// Expect registers that `method` has to be called with the parent Key
+// `parentKey` before the test ends, `parentKey` is ignored if it is empty.
+func (m *Mock) Expect(method, parentKey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = append(m.expectations, expectation{method, parentKey})
+}
+
+// AssertExpectations fails the test for every expected call that was not made.
+func (m *Mock) AssertExpectations(t testing.TB) {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range m.expectations {
+		if !m.called(e) {
+			t.Errorf("expected call %s(%q) was not made, calls: %v", e.method, e.parentKey, m.calls)
+		}
+	}
+}
+
+func (m *Mock) called(e expectation) bool {
+	for _, c := range m.calls {
+		if c.Method == e.method && (e.parentKey == "" || c.ParentKey == e.parentKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Calls returns all calls in the order they were made.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// call records the call and returns the injected error, if any.
+func (m *Mock) call(method string, parentKey elektra.Key) error {
+	if m.Latency > 0 {
+		time.Sleep(m.Latency)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := Call{Method: method}
+
+	if parentKey != nil {
+		c.ParentKey = parentKey.Name()
+	}
+
+	m.calls = append(m.calls, c)
+	m.counts[method]++
+
+	for _, f := range m.faults {
+		if f.method == method && (f.n == 0 || f.n == m.counts[method]) {
+			return f.err
+		}
+	}
+
+	return nil
+}
+
+// Open implements KDB.
+func (m *Mock) Open() error {
+	return m.call("Open", nil)
+}
+
+// Close implements KDB.
+func (m *Mock) Close() error {
+	return m.call("Close", nil)
+}
+
+// Get copies the Keys below `parentKey` from Data to `keySet`.
+func (m *Mock) Get(keySet elektra.KeySet, parentKey elektra.Key) (bool, error) {
+	if err := m.call("Get", parentKey); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := removeBelow(keySet, parentKey); err != nil {
+		return false, err
+	}
+
+	below := m.Data.Below(parentKey)
+	defer below.Close()
+
+	for _, k := range below.ToSlice() {
+		keySet.AppendKey(k.Duplicate(elektra.KEY_CP_ALL))
+	}
+
+	return below.Len() > 0, nil
+}
+
+// Set replaces the Keys below `parentKey` in Data with the ones of `keySet`.
+func (m *Mock) Set(keySet elektra.KeySet, parentKey elektra.Key) (bool, error) {
+	if err := m.call("Set", parentKey); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	below := keySet.Below(parentKey)
+	defer below.Close()
+
+	current := m.Data.Below(parentKey)
+	defer current.Close()
+
+	if below.Equal(current, true) {
+		return false, nil
+	}
+
+	if err := removeBelow(m.Data, parentKey); err != nil {
+		return false, err
+	}
+
+	for _, k := range below.ToSlice() {
+		m.Data.AppendKey(k.Duplicate(elektra.KEY_CP_ALL))
+	}
+
+	return true, nil
+}
+
+// GetWithWarnings works like Get and reports the warnings registered with Warn.
+func (m *Mock) GetWithWarnings(keySet elektra.KeySet, parentKey elektra.Key) (bool, []elektra.ElektraWarning, error) {
+	changed, err := m.Get(keySet, parentKey)
+
+	return changed, m.warningsOf("Get"), err
+}
+
+// SetWithWarnings works like Set and reports the warnings registered with Warn.
+func (m *Mock) SetWithWarnings(keySet elektra.KeySet, parentKey elektra.Key) (bool, []elektra.ElektraWarning, error) {
+	changed, err := m.Set(keySet, parentKey)
+
+	return changed, m.warningsOf("Set"), err
+}
+
+// Version returns VersionString.
+func (m *Mock) Version() (string, error) {
+	if err := m.call("Version", nil); err != nil {
+		return "", err
+	}
+
+	return m.VersionString, nil
+}
+
+func removeBelow(ks elektra.KeySet, parentKey elektra.Key) error {
+	if parentKey == nil {
+		return fmt.Errorf("%w: missing parent key", elektra.ErrInterface)
+	}
+
+	if err := elektra.RemoveTree(ks, parentKey); err != nil && !errors.Is(err, elektra.ErrKeyNotFound) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package kdbtest_test
+
+import (
+	"errors"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/kdb/kdbtest"
+	. "go.libelektra.org/test"
+)
+
+func TestMockGetSet(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	mock.Expect("Set", "user:/tests/go/mock")
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/mock")
+	key, _ := elektra.NewKey("user:/tests/go/mock/key", "value")
+
+	ks := elektra.NewKeySet(key)
+	changed, err := mock.Set(ks, parentKey)
+	Check(t, err, "Set failed")
+	Assert(t, changed, "Set should change the mock")
+
+	got := elektra.NewKeySet()
+	changed, err = mock.Get(got, parentKey)
+	Check(t, err, "Get failed")
+	Assert(t, changed, "Get should return Keys")
+	kdbtest.AssertKeySetEqual(t, ks, got)
+
+	calls := mock.Calls()
+	Assertf(t, len(calls) == 2 && calls[1].Method == "Get", "unexpected calls %v", calls)
+}
+
+func TestMockFail(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	mock.Fail("Set", 2, kdbtest.Error(elektra.ErrConflictingState, "modified by another process"))
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/mock")
+	key, _ := elektra.NewKey("user:/tests/go/mock/key", "value")
+	ks := elektra.NewKeySet(key)
+
+	_, err := mock.Set(ks, parentKey)
+	Check(t, err, "first Set should succeed")
+
+	_, err = mock.Set(ks, parentKey)
+	Assert(t, errors.Is(err, elektra.ErrConflictingState), "second Set should return a conflict")
+
+	var conflict elektra.ConflictingStateError
+	Assert(t, errors.As(err, &conflict), "error should be a ConflictingStateError")
+	Assert(t, conflict.Number == "C02000", "wrong error number")
+}
+
+func TestMockWarn(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	mock.Warn("Get", elektra.ElektraWarning{ElektraError: *kdbtest.Error(elektra.ErrPluginMisbehavior, "deprecated key")})
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/mock")
+
+	_, warnings, err := elektra.GetWithWarnings(mock, elektra.NewKeySet(), parentKey)
+	Check(t, err, "Get should succeed")
+	Assertf(t, len(warnings) == 1 && warnings[0].Reason == "deprecated key", "unexpected warnings %v", warnings)
+
+	_, warnings, err = elektra.SetWithWarnings(mock, elektra.NewKeySet(), parentKey)
+	Check(t, err, "Set should succeed")
+	Assertf(t, len(warnings) == 0, "Set should not report the warnings of Get: %v", warnings)
+}
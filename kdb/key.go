@@ -47,6 +47,7 @@ const (
 type Key interface {
 	Name() string
 	Namespace() ElektraNamespace
+	WithNamespace(ns ElektraNamespace) Key
 	BaseName() string
 
 	String() string
@@ -58,6 +59,9 @@ type Key interface {
 	MetaMap() map[string]string
 	RemoveMeta(name string) error
 	MetaSlice() []Key
+	HasMeta(name string) bool
+	MetaInt(name string) (int64, error)
+	MetaBool(name string) (bool, error)
 
 	IsBelow(key Key) bool
 	IsBelowOrSame(key Key) bool
@@ -66,8 +70,14 @@ type Key interface {
 
 	Duplicate(flags KeyCopyFlags) Key
 
+	Lock(flags KeyLockFlags) error
+	IsLocked(flags KeyLockFlags) bool
+
 	SetMeta(name, value string) error
+	CopyMeta(src Key, name string) error
+	CopyAllMeta(src Key) error
 	SetName(name string) error
+	SetNamespace(ns ElektraNamespace) error
 	SetString(value string) error
 	SetBytes(value []byte) error
 }
@@ -89,7 +99,7 @@ func newKey(name string, value ...interface{}) (*CKey, error) {
 	n := C.CString(name)
 	defer C.free(unsafe.Pointer(n))
 
-	if name == "" {
+	if name == "" || strings.ContainsRune(name, 0) {
 		return nil, errors.New("unsupported key name")
 	} else if len(value) > 0 {
 		switch v := value[0].(type) {
@@ -121,6 +131,17 @@ func wrapKey(k *C.struct__Key) *CKey {
 	return key
 }
 
+// WrapKeyPtr wraps a `Key *` that was created in C, e.g. a Key that
+// libelektra passes to a plugin. The Key is still owned by C and
+// must not be closed.
+func WrapKeyPtr(ptr unsafe.Pointer) Key {
+	if ptr == nil {
+		return nil
+	}
+
+	return wrapKey((*C.struct__Key)(ptr))
+}
+
 // Close free's the underlying key's memory. This needs to be done
 // for Keys that are created by NewKey() or Key.Duplicate().
 func (k *CKey) Close() {
@@ -151,6 +172,25 @@ func (k *CKey) BaseName() string {
 	return C.GoString(name)
 }
 
+// EscapeBaseName escapes `name` the way Elektra escapes base names,
+// so it can be used as a single part of a key name.
+func EscapeBaseName(name string) string {
+	root := C.CString("/")
+	defer C.free(unsafe.Pointer(root))
+
+	k := C.keyNewWrapper(root)
+	defer C.keyDel(k)
+
+	n := C.CString(name)
+	defer C.free(unsafe.Pointer(n))
+
+	if C.keyAddBaseName(k, n) < 0 {
+		return strings.NewReplacer(`\`, `\\`, `/`, `\/`).Replace(name)
+	}
+
+	return strings.TrimPrefix(C.GoString(C.keyName(k)), "/")
+}
+
 // Name returns the name of the Key.
 func (k *CKey) Name() string {
 	name := C.keyName(k.Ptr)
@@ -165,7 +205,9 @@ func (k *CKey) SetBytes(value []byte) error {
 
 	size := C.ulong(len(value))
 
-	C.keySetBinary(k.Ptr, unsafe.Pointer(v), size)
+	if ret := C.keySetBinary(k.Ptr, unsafe.Pointer(v), size); ret < 0 {
+		return k.lockedError(KEY_LOCK_VALUE, "could not set value")
+	}
 
 	return nil
 }
@@ -175,7 +217,9 @@ func (k *CKey) SetString(value string) error {
 	v := C.CString(value)
 	defer C.free(unsafe.Pointer(v))
 
-	_ = C.keySetString(k.Ptr, v)
+	if ret := C.keySetString(k.Ptr, v); ret < 0 {
+		return k.lockedError(KEY_LOCK_VALUE, "could not set value")
+	}
 
 	return nil
 }
@@ -194,11 +238,16 @@ func (k *CKey) SetBoolean(value bool) error {
 
 // SetName sets the name of the Key.
 func (k *CKey) SetName(name string) error {
+	// C strings end at the first NUL byte, which would silently truncate the name
+	if strings.ContainsRune(name, 0) {
+		return errors.New("could not set key name")
+	}
+
 	n := C.CString(name)
 	defer C.free(unsafe.Pointer(n))
 
 	if ret := C.keySetName(k.Ptr, n); ret < 0 {
-		return errors.New("could not set key name")
+		return k.lockedError(KEY_LOCK_NAME, "could not set key name")
 	}
 
 	return nil
@@ -239,7 +288,7 @@ func (k *CKey) SetMeta(name, value string) error {
 	ret := C.keySetMeta(k.Ptr, cName, cValue)
 
 	if ret < 0 {
-		return errors.New("could not set meta")
+		return k.lockedError(KEY_LOCK_META, "could not set meta")
 	}
 
 	return nil
@@ -254,7 +303,7 @@ func (k *CKey) RemoveMeta(name string) error {
 	ret := C.keySetMeta(k.Ptr, cName, nil)
 
 	if ret < 0 {
-		return errors.New("could not delete meta")
+		return k.lockedError(KEY_LOCK_META, "could not delete meta")
 	}
 
 	return nil
@@ -382,21 +431,45 @@ func CommonKeyName(key1, key2 Key) string {
 		return key1Name
 	}
 
-	key1Path := nameWithoutNamespace(key1)
-	key2Path := nameWithoutNamespace(key2)
+	sameNamespace := key1.Namespace() == key2.Namespace()
 
 	ns := "/"
-	if key1.Namespace() == key2.Namespace() {
+	if sameNamespace {
 		ns = key1Name[:strings.Index(key1Name, "/")] + "/"
-	} else if key1Path[2] != key2Path[2] {
-		return ""
 	}
 
 	index := 0
-	k1Parts, k2Parts := strings.Split(key1Path[1:], "/"), strings.Split(key2Path[1:], "/")
+	k1Parts, k2Parts := splitKeyName(nameWithoutNamespace(key1)), splitKeyName(nameWithoutNamespace(key2))
 
 	for ; index < len(k1Parts) && index < len(k2Parts) && k1Parts[index] == k2Parts[index]; index++ {
 	}
 
+	if !sameNamespace && index == 0 {
+		return ""
+	}
+
 	return ns + strings.Join(k1Parts[:index], "/")
 }
+
+// splitKeyName splits the name `path` without namespace at unescaped slashes.
+func splitKeyName(path string) []string {
+	var parts []string
+
+	start := 1
+
+	for i := 1; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			i++
+		case '/':
+			parts = append(parts, path[start:i])
+			start = i + 1
+		}
+	}
+
+	if start < len(path) {
+		parts = append(parts, path[start:])
+	}
+
+	return parts
+}
@@ -2,6 +2,7 @@ package kdb_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -24,6 +25,15 @@ func TestName(t *testing.T) {
 	Assert(t, k.Name() == secondName, "could not set name")
 }
 
+func TestEscapeBaseName(t *testing.T) {
+	escaped := elektra.EscapeBaseName(`a/b\c`)
+	Assertf(t, escaped == `a\/b\\c`, "wrong escaped base name %q", escaped)
+
+	k, err := elektra.NewKey("user:/tests/go/elektra/" + escaped)
+	Check(t, err, "could not create key with escaped base name")
+	Assertf(t, k.BaseName() == `a/b\c`, "escaped name has base name %q", k.BaseName())
+}
+
 func TestString(t *testing.T) {
 	testValue := "Hello World"
 
@@ -43,6 +53,10 @@ var bytesTests = []struct {
 	{"proc:/foo/bar", "user:/foo/bar", "/foo/bar"},
 	{"user:/foo/bar", "user:/bar/foo", "user:/"},
 	{"proc:/bar/foo", "user:/foo/bar", ""},
+	{"proc:/a", "user:/b", ""},
+	{"proc:/a", "user:/a/b", "/a"},
+	{`user:/a\/b/c`, `user:/a\/b/d`, `user:/a\/b`},
+	{`user:/a\/b`, `user:/a\/c`, "user:/"},
 }
 
 func TestBytes(t *testing.T) {
@@ -167,3 +181,123 @@ func TestCommonKeyName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNamespace(t *testing.T) {
+	for _, ns := range elektra.Namespaces() {
+		parsed, err := elektra.ParseNamespace(ns.String())
+		Check(t, err, "could not parse namespace")
+		Assertf(t, parsed == ns, "parsed namespace should be %q but is %q", ns, parsed)
+	}
+
+	ns, err := elektra.ParseNamespace("user:/")
+	Check(t, err, "could not parse namespace")
+	Assertf(t, ns == elektra.KEY_NS_USER, "namespace should be user but is %q", ns)
+
+	_, err = elektra.ParseNamespace("invalid")
+	Assert(t, err == elektra.ErrInvalidNamespace, "parsing an invalid namespace should fail")
+}
+
+func TestNamespaceKeyName(t *testing.T) {
+	for ns, expected := range map[elektra.ElektraNamespace]string{
+		elektra.KEY_NS_USER:      "user:/sw/app",
+		elektra.KEY_NS_DEFAULT:   "default:/sw/app",
+		elektra.KEY_NS_CASCADING: "/sw/app",
+	} {
+		name := ns.KeyName("/sw/app")
+		Assertf(t, name == expected, "name should be %q but is %q", expected, name)
+	}
+
+	Assert(t, elektra.KEY_NS_SYSTEM.KeyName("sw") == "system:/sw", "a missing leading slash should be added")
+}
+
+func TestSetNamespace(t *testing.T) {
+	key, _ := elektra.NewKey("user:/tests/go/elektra/namespace", "value")
+
+	err := key.SetNamespace(elektra.KEY_NS_SYSTEM)
+	Check(t, err, "could not set namespace")
+	Assertf(t, key.Name() == "system:/tests/go/elektra/namespace", "wrong name %q", key.Name())
+
+	dup := key.WithNamespace(elektra.KEY_NS_DIR)
+	Assertf(t, dup.Name() == "dir:/tests/go/elektra/namespace", "wrong name %q", dup.Name())
+	Assert(t, dup.String() == "value", "WithNamespace should keep the value")
+	Assert(t, key.Namespace() == elektra.KEY_NS_SYSTEM, "WithNamespace should not modify the Key")
+}
+
+func TestTypedMeta(t *testing.T) {
+	key, _ := elektra.NewKey("user:/tests/go/elektra/meta/typed")
+	_ = key.SetMeta("check/range", "42")
+	_ = key.SetMeta("visible", "1")
+
+	Assert(t, key.HasMeta("visible"), "Key should have meta key visible")
+	Assert(t, !key.HasMeta("missing"), "Key should not have meta key missing")
+
+	i, err := key.MetaInt("check/range")
+	Check(t, err, "MetaInt failed")
+	Assertf(t, i == 42, "MetaInt should be 42 but is %d", i)
+
+	b, err := key.MetaBool("visible")
+	Check(t, err, "MetaBool failed")
+	Assert(t, b, "MetaBool should be true")
+
+	_, err = key.MetaInt("missing")
+	Assert(t, errors.Is(err, elektra.ErrMetaNotFound), "missing meta key should return ErrMetaNotFound")
+}
+
+func TestCopyMeta(t *testing.T) {
+	src, _ := elektra.NewKey("user:/tests/go/elektra/meta/src")
+	_ = src.SetMeta("type", "long")
+	_ = src.SetMeta("default", "7")
+
+	dst, _ := elektra.NewKey("user:/tests/go/elektra/meta/dst")
+
+	err := dst.CopyMeta(src, "type")
+	Check(t, err, "CopyMeta failed")
+	Assert(t, dst.Meta("type") == "long", "meta key type was not copied")
+	Assert(t, !dst.HasMeta("default"), "CopyMeta should only copy one meta key")
+
+	err = dst.CopyAllMeta(src)
+	Check(t, err, "CopyAllMeta failed")
+	Assert(t, dst.Meta("default") == "7", "meta key default was not copied")
+}
+
+func TestComments(t *testing.T) {
+	key, _ := elektra.NewKey("user:/tests/go/elektra/meta/comments")
+
+	comments := make([]elektra.Comment, 12)
+	comments[0] = elektra.Comment{Text: " inline", Start: "#", Space: 1}
+	comments[11] = elektra.Comment{Text: " last", Start: ";"}
+
+	err := elektra.SetComments(key, comments)
+	Check(t, err, "SetComments failed")
+	Assert(t, key.Meta("comment/#_11") == " last", "comment #_11 was not set")
+
+	got := elektra.Comments(key)
+	Assertf(t, len(got) == len(comments), "expected %d comments but got %d", len(comments), len(got))
+	Assertf(t, got[0] == comments[0], "first comment should be %v but is %v", comments[0], got[0])
+	Assertf(t, got[11] == comments[11], "last comment should be %v but is %v", comments[11], got[11])
+
+	err = elektra.SetComments(key, nil)
+	Check(t, err, "SetComments failed")
+	Assert(t, len(elektra.Comments(key)) == 0, "comments were not removed")
+}
+
+func TestLock(t *testing.T) {
+	key, _ := elektra.NewKey("user:/tests/go/elektra/lock", "value")
+
+	Assert(t, !key.IsLocked(elektra.KEY_LOCK_VALUE), "new Key should not be locked")
+
+	err := key.Lock(elektra.KEY_LOCK_VALUE | elektra.KEY_LOCK_META)
+	Check(t, err, "could not lock Key")
+	Assert(t, key.IsLocked(elektra.KEY_LOCK_VALUE), "value should be locked")
+	Assert(t, !key.IsLocked(elektra.KEY_LOCK_ALL), "name should not be locked")
+
+	err = key.SetString("changed")
+	Assert(t, errors.Is(err, elektra.ErrKeyLocked), "setting a locked value should return ErrKeyLocked")
+	Assert(t, key.String() == "value", "locked value was changed")
+
+	err = key.SetMeta("type", "string")
+	Assert(t, errors.Is(err, elektra.ErrKeyLocked), "setting locked meta should return ErrKeyLocked")
+
+	err = key.SetName("user:/tests/go/elektra/lock/renamed")
+	Check(t, err, "could not rename Key with unlocked name")
+}
@@ -9,6 +9,7 @@ package kdb
 import "C"
 
 import (
+	"strings"
 	"unsafe"
 
 	"errors"
@@ -27,6 +28,9 @@ type KeySet interface {
 	Len() int
 
 	Cut(key Key) KeySet
+	Below(parent Key) KeySet
+	Children(parent Key) []Key
+	HasBelow(parent Key) bool
 
 	Close()
 
@@ -38,10 +42,20 @@ type KeySet interface {
 
 	Lookup(key Key) Key
 	LookupByName(name string) Key
+	Resolve(name string) (Key, ElektraNamespace, error)
+
+	ByNamespace(ns ElektraNamespace) KeySet
+
+	Freeze() error
+
+	Equal(other KeySet, compareMeta bool) bool
 }
 
 type CKeySet struct {
 	Ptr *C.struct__KeySet
+
+	// frozen is set by Freeze, the KeySet may not be modified anymore
+	frozen bool
 }
 
 // NewKeySet creates a new KeySet.
@@ -66,6 +80,17 @@ func wrapKeySet(ks *C.struct__KeySet) *CKeySet {
 	return keySet
 }
 
+// WrapKeySetPtr wraps a `KeySet *` that was created in C, e.g. a KeySet
+// that libelektra passes to a plugin. The KeySet is still owned by C and
+// must not be closed.
+func WrapKeySetPtr(ptr unsafe.Pointer) KeySet {
+	if ptr == nil {
+		return nil
+	}
+
+	return wrapKeySet((*C.struct__KeySet)(ptr))
+}
+
 // Close needs to be called on a KeySet after it is not in
 // use anymore to free the allocated memory.
 func (ks *CKeySet) Close() {
@@ -88,11 +113,11 @@ func toCKeySet(keySet KeySet) (*CKeySet, error) {
 
 // Append appends all Keys from `other` to this KeySet and returns the
 // new length of this KeySet or -1 if `other` is not a KeySet which was
-// created by elektra/kdb.
+// created by elektra/kdb or this KeySet is frozen.
 func (ks *CKeySet) Append(other KeySet) int {
 	ckeySet, err := toCKeySet(other)
 
-	if err != nil {
+	if err != nil || ks.frozen {
 		return -1
 	}
 
@@ -108,11 +133,11 @@ func (ks *CKeySet) Duplicate() KeySet {
 
 // AppendKey appends a Key to this KeySet and returns the new
 // length of this KeySet or -1 if the key is
-// not a Key created by elektra/kdb.
+// not a Key created by elektra/kdb or this KeySet is frozen.
 func (ks *CKeySet) AppendKey(key Key) int {
 	ckey, err := toCKey(key)
 
-	if err != nil {
+	if err != nil || ks.frozen {
 		return -1
 	}
 
@@ -121,11 +146,12 @@ func (ks *CKeySet) AppendKey(key Key) int {
 	return size
 }
 
-// Cut cuts out a new KeySet at the cutpoint key and returns it.
+// Cut cuts out a new KeySet at the cutpoint key and returns it,
+// it returns nil if this KeySet is frozen.
 func (ks *CKeySet) Cut(key Key) KeySet {
 	k, err := toCKey(key)
 
-	if err != nil {
+	if err != nil || ks.frozen {
 		return nil
 	}
 
@@ -134,6 +160,86 @@ func (ks *CKeySet) Cut(key Key) KeySet {
 	return newKs
 }
 
+// Below returns a new KeySet with `parent` and all Keys below it without
+// modifying this KeySet. The Keys are shared, not copied.
+func (ks *CKeySet) Below(parent Key) KeySet {
+	k, err := toCKey(parent)
+
+	if err != nil {
+		return nil
+	}
+
+	return wrapKeySet(ksBelow(ks.Ptr, k.Ptr))
+}
+
+// Children returns the Keys directly below `parent`.
+func (ks *CKeySet) Children(parent Key) []Key {
+	k, err := toCKey(parent)
+
+	if err != nil {
+		return nil
+	}
+
+	var children []Key
+
+	// ksFindHierarchy does not consider other namespaces for cascading Keys
+	if k.Namespace() == KEY_NS_CASCADING {
+		below := ks.Below(k)
+		defer below.Close()
+
+		below.ForEach(func(child Key, _ int) {
+			if child.IsDirectlyBelow(k) {
+				children = append(children, child)
+			}
+		})
+
+		return children
+	}
+
+	start, end := ksFindHierarchy(ks.Ptr, k.Ptr)
+
+	for it := start; it < end; it++ {
+		child := wrapKey(C.ksAtCursor(ks.Ptr, it))
+
+		if child.IsDirectlyBelow(k) {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
+// HasBelow checks if the KeySet contains Keys below `parent`,
+// `parent` itself is not considered.
+func (ks *CKeySet) HasBelow(parent Key) bool {
+	k, err := toCKey(parent)
+
+	if err != nil {
+		return false
+	}
+
+	if k.Namespace() == KEY_NS_CASCADING {
+		below := ks.Below(k)
+		defer below.Close()
+
+		for _, child := range below.ToSlice() {
+			if child.IsBelow(k) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	start, end := ksFindHierarchy(ks.Ptr, k.Ptr)
+
+	if start < end && C.keyCmp(C.ksAtCursor(ks.Ptr, start), k.Ptr) == 0 {
+		start++
+	}
+
+	return start < end
+}
+
 // ToSlice returns a slice containing all Keys.
 func (ks *CKeySet) ToSlice() []Key {
 	var keys = make([]Key, ks.Len())
@@ -199,11 +305,12 @@ func (ks *CKeySet) KeyNames() []string {
 	return keys
 }
 
-// Copy copies the entire KeySet to the passed KeySet.
+// Copy copies the entire KeySet to the passed KeySet,
+// unless the passed KeySet is frozen.
 func (ks *CKeySet) Copy(keySet KeySet) {
 	cKeySet, err := toCKeySet(keySet)
 
-	if err != nil {
+	if err != nil || cKeySet.frozen {
 		return
 	}
 
@@ -212,38 +319,57 @@ func (ks *CKeySet) Copy(keySet KeySet) {
 	return
 }
 
-// Pop removes and returns the last Element that was added to the KeySet.
+// Pop removes and returns the last Element that was added to the KeySet,
+// it returns nil if the KeySet is frozen.
 func (ks *CKeySet) Pop() Key {
+	if ks.frozen {
+		return nil
+	}
+
 	key := C.ksPop(ks.Ptr)
 
 	return wrapKey(key)
 }
 
-// Remove removes a key from the KeySet and returns it if found.
+// Remove removes a key from the KeySet and returns it if found,
+// a frozen KeySet is not modified.
 func (ks *CKeySet) Remove(key Key) Key {
 	ckey, err := toCKey(key)
 
-	if err != nil {
+	if err != nil || ks.frozen {
 		return nil
 	}
 
-	removed := C.ksLookup(ks.Ptr, ckey.Ptr, C.KDB_O_POP)
+	if removed := C.ksLookup(ks.Ptr, ckey.Ptr, C.KDB_O_POP); removed != nil {
+		return wrapKey(removed)
+	}
 
-	return wrapKey(removed)
+	return nil
 }
 
-// RemoveByName removes a key by its name from the KeySet and returns it if found.
+// RemoveByName removes a key by its name from the KeySet and returns it if found,
+// a frozen KeySet is not modified.
 func (ks *CKeySet) RemoveByName(name string) Key {
+	if ks.frozen {
+		return nil
+	}
+
 	n := C.CString(name)
 	defer C.free(unsafe.Pointer(n))
 
-	key := C.ksLookupByName(ks.Ptr, n, C.KDB_O_POP)
+	if key := C.ksLookupByName(ks.Ptr, n, C.KDB_O_POP); key != nil {
+		return wrapKey(key)
+	}
 
-	return wrapKey(key)
+	return nil
 }
 
-// Clear removes all Keys from the KeySet.
+// Clear removes all Keys from the KeySet, unless it is frozen.
 func (ks *CKeySet) Clear() {
+	if ks.frozen {
+		return
+	}
+
 	root, _ := newKey("/")
 
 	// don't use `ksClear` because it is internal
@@ -271,6 +397,10 @@ func (ks *CKeySet) Lookup(key Key) Key {
 
 // LookupByName searches the KeySet for a Key by name.
 func (ks *CKeySet) LookupByName(name string) Key {
+	if strings.ContainsRune(name, 0) {
+		return nil
+	}
+
 	n := C.CString(name)
 	defer C.free(unsafe.Pointer(n))
 
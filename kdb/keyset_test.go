@@ -1,6 +1,7 @@
 package kdb_test
 
 import (
+	"errors"
 	"testing"
 
 	elektra "go.libelektra.org/kdb"
@@ -89,6 +90,9 @@ func TestRemove(t *testing.T) {
 	removed = ks.RemoveByName("user:/tests/go/elektra/remove/2")
 	Assert(t, removed != nil, "RemoveByName failed")
 	Assert(t, ks.Len() == 1, "KeySet should have length 2")
+
+	removed = ks.RemoveByName("user:/tests/go/elektra/remove/2")
+	Assert(t, removed == nil, "RemoveByName of a missing key should return nil")
 }
 
 func TestClearKeySet(t *testing.T) {
@@ -121,3 +125,219 @@ func TestLookupByName(t *testing.T) {
 	Assertf(t, foundKey.Name() == keyName,
 		"the name of Key found by LookupByName() should be %q but is %q", k.Name(), foundKey.Name())
 }
+
+func resolveKeySet(t *testing.T, keys map[string]string, meta map[string]map[string]string) elektra.KeySet {
+	t.Helper()
+
+	ks := elektra.NewKeySet()
+
+	for name, value := range keys {
+		k, err := elektra.NewKey(name, value)
+		Check(t, err, "could not create Key")
+
+		for metaName, metaValue := range meta[name] {
+			err = k.SetMeta(metaName, metaValue)
+			Check(t, err, "could not set meta")
+		}
+
+		ks.AppendKey(k)
+	}
+
+	return ks
+}
+
+func TestResolveNamespaceOrder(t *testing.T) {
+	ks := resolveKeySet(t, map[string]string{
+		"system:/tests/go/elektra/resolve": "system",
+		"user:/tests/go/elektra/resolve":   "user",
+	}, nil)
+
+	key, ns, err := ks.Resolve("/tests/go/elektra/resolve")
+	Check(t, err, "Resolve failed")
+	Assertf(t, ns == elektra.KEY_NS_USER, "Key should be resolved from user:/ but was from %d", ns)
+	Assertf(t, key.String() == "user", "wrong Key resolved: %q", key.String())
+}
+
+func TestResolveOverrideAndFallback(t *testing.T) {
+	ks := resolveKeySet(t, map[string]string{
+		"spec:/tests/go/elektra/resolve/key":      "",
+		"user:/tests/go/elektra/resolve/key":      "user",
+		"system:/tests/go/elektra/resolve/other":  "override",
+		"system:/tests/go/elektra/resolve/legacy": "fallback",
+		"spec:/tests/go/elektra/resolve/missing":  "",
+	}, map[string]map[string]string{
+		"spec:/tests/go/elektra/resolve/key": {
+			"override/#0": "/tests/go/elektra/resolve/nothing",
+			"override/#1": "/tests/go/elektra/resolve/other",
+		},
+		"spec:/tests/go/elektra/resolve/missing": {
+			"fallback/#0": "/tests/go/elektra/resolve/legacy",
+			"default":     "default",
+		},
+	})
+
+	key, ns, trace, err := elektra.ResolveExplain(ks, "/tests/go/elektra/resolve/key")
+	Check(t, err, "Resolve failed")
+	Assertf(t, key.String() == "override", "override should win but got %q, trace: %v", key.String(), trace)
+	Assertf(t, ns == elektra.KEY_NS_SYSTEM, "Key should be resolved from system:/ but was from %d", ns)
+	Assert(t, len(trace) > 0, "trace should not be empty")
+
+	key, _, err = ks.Resolve("/tests/go/elektra/resolve/missing")
+	Check(t, err, "Resolve failed")
+	Assertf(t, key.String() == "fallback", "fallback should be used but got %q", key.String())
+}
+
+func TestResolveDefault(t *testing.T) {
+	ks := resolveKeySet(t, map[string]string{
+		"spec:/tests/go/elektra/resolve/default": "",
+	}, map[string]map[string]string{
+		"spec:/tests/go/elektra/resolve/default": {
+			"default":     "42",
+			"fallback/#0": "/tests/go/elektra/resolve/default",
+		},
+	})
+
+	key, ns, err := ks.Resolve("/tests/go/elektra/resolve/default")
+	Check(t, err, "Resolve failed")
+	Assertf(t, ns == elektra.KEY_NS_DEFAULT, "Key should be resolved from default:/ but was from %d", ns)
+	Assertf(t, key.String() == "42", "wrong default value %q", key.String())
+	Assert(t, ks.LookupByName("default:/tests/go/elektra/resolve/default") != nil, "default Key should be added to the KeySet")
+
+	_, _, err = ks.Resolve("/tests/go/elektra/resolve/unknown")
+	Assert(t, errors.Is(err, elektra.ErrKeyNotFound), "resolving an unknown Key should return ErrKeyNotFound")
+}
+
+func TestByNamespace(t *testing.T) {
+	ks := resolveKeySet(t, map[string]string{
+		"user:/tests/go/elektra/ns/1":   "",
+		"user:/tests/go/elektra/ns/2":   "",
+		"system:/tests/go/elektra/ns/1": "",
+	}, nil)
+
+	user := ks.ByNamespace(elektra.KEY_NS_USER)
+	Assertf(t, user.Len() == 2, "expected 2 user Keys but got %d", user.Len())
+	Assert(t, ks.Len() == 3, "ByNamespace should not modify the KeySet")
+}
+
+func TestFreeze(t *testing.T) {
+	ks := resolveKeySet(t, map[string]string{
+		"user:/tests/go/elektra/freeze/1": "1",
+		"user:/tests/go/elektra/freeze/2": "2",
+	}, nil)
+
+	err := ks.Freeze()
+	Check(t, err, "could not freeze KeySet")
+
+	ks.ForEach(func(k elektra.Key, _ int) {
+		Assertf(t, k.IsLocked(elektra.KEY_LOCK_ALL), "Key %q should be locked", k.Name())
+	})
+
+	err = ks.LookupByName("user:/tests/go/elektra/freeze/1").SetString("changed")
+	Assert(t, errors.Is(err, elektra.ErrKeyLocked), "changing a frozen Key should return ErrKeyLocked")
+
+	k, _ := elektra.NewKey("user:/tests/go/elektra/freeze/3")
+	defer k.Close()
+
+	Assert(t, ks.AppendKey(k) == -1, "appending to a frozen KeySet should fail")
+	Assert(t, ks.RemoveByName("user:/tests/go/elektra/freeze/2") == nil, "removing from a frozen KeySet should fail")
+
+	ks.Clear()
+	Assertf(t, ks.Len() == 2, "a frozen KeySet should not be modified but contains %d Keys", ks.Len())
+}
+
+func TestBelow(t *testing.T) {
+	ks := resolveKeySet(t, map[string]string{
+		"user:/tests/go/elektra/below":         "",
+		"user:/tests/go/elektra/below/a":       "",
+		"user:/tests/go/elektra/below/a/b":     "",
+		"system:/tests/go/elektra/below/c":     "",
+		"user:/tests/go/elektra/belowsibling":  "",
+		"user:/tests/go/elektra/leaf":          "",
+		"system:/tests/go/elektra/below/c/d/e": "",
+	}, nil)
+
+	parent, _ := elektra.NewKey("user:/tests/go/elektra/below")
+	below := ks.Below(parent)
+
+	Assertf(t, below.Len() == 3, "expected 3 Keys below %q but got %d", parent.Name(), below.Len())
+	Assert(t, ks.Len() == 7, "Below should not modify the KeySet")
+
+	children := ks.Children(parent)
+	Assertf(t, len(children) == 1, "expected 1 child but got %d", len(children))
+	Assert(t, ks.HasBelow(parent), "HasBelow should be true")
+
+	leaf, _ := elektra.NewKey("user:/tests/go/elektra/leaf")
+	Assert(t, !ks.HasBelow(leaf), "HasBelow should be false for a leaf")
+
+	cascading, _ := elektra.NewKey("/tests/go/elektra/below")
+	children = ks.Children(cascading)
+	Assertf(t, len(children) == 2, "expected 2 cascading children but got %d", len(children))
+	Assert(t, ks.HasBelow(cascading), "HasBelow should be true for cascading Keys")
+}
+
+func TestUnion(t *testing.T) {
+	a := resolveKeySet(t, map[string]string{
+		"user:/tests/go/elektra/union/a":    "a",
+		"user:/tests/go/elektra/union/both": "a",
+	}, map[string]map[string]string{
+		"user:/tests/go/elektra/union/both": {"type": "string"},
+	})
+	b := resolveKeySet(t, map[string]string{
+		"user:/tests/go/elektra/union/b":    "b",
+		"user:/tests/go/elektra/union/both": "b",
+	}, map[string]map[string]string{
+		"user:/tests/go/elektra/union/both": {"type": "long", "default": "1"},
+	})
+
+	union, err := elektra.Union(a, b, elektra.PreferFirst)
+	Check(t, err, "Union failed")
+	Assertf(t, union.Len() == 3, "expected 3 Keys but got %d", union.Len())
+	Assert(t, union.LookupByName("user:/tests/go/elektra/union/both").String() == "a", "first KeySet should win")
+
+	union, err = elektra.Union(a, b, elektra.PreferSecond|elektra.MergeMeta)
+	Check(t, err, "Union failed")
+
+	both := union.LookupByName("user:/tests/go/elektra/union/both")
+	Assert(t, both.String() == "b", "second KeySet should win")
+	Assert(t, both.Meta("type") == "long", "meta of the kept Key should win")
+	Assert(t, both.Meta("default") == "1", "meta of the kept Key should be kept")
+	Assert(t, !a.LookupByName("user:/tests/go/elektra/union/both").HasMeta("default"), "Union should not modify its inputs")
+}
+
+func TestIntersectSubtract(t *testing.T) {
+	a := resolveKeySet(t, map[string]string{
+		"user:/tests/go/elektra/setops/1": "",
+		"user:/tests/go/elektra/setops/2": "",
+		"user:/tests/go/elektra/setops/3": "",
+	}, nil)
+	b := resolveKeySet(t, map[string]string{
+		"user:/tests/go/elektra/setops/2": "",
+		"user:/tests/go/elektra/setops/4": "",
+	}, nil)
+
+	intersection := elektra.Intersect(a, b)
+	Assertf(t, intersection.Len() == 1, "expected 1 Key but got %d", intersection.Len())
+	Assert(t, intersection.LookupByName("user:/tests/go/elektra/setops/2") != nil, "intersection is missing a Key")
+
+	difference := elektra.Subtract(a, b)
+	Assertf(t, difference.Len() == 2, "expected 2 Keys but got %d", difference.Len())
+	Assert(t, difference.LookupByName("user:/tests/go/elektra/setops/2") == nil, "difference contains a subtracted Key")
+}
+
+func TestKeySetEqual(t *testing.T) {
+	keys := map[string]string{
+		"user:/tests/go/elektra/equal/1": "1",
+		"user:/tests/go/elektra/equal/2": "2",
+	}
+
+	a := resolveKeySet(t, keys, nil)
+	b := resolveKeySet(t, keys, map[string]map[string]string{
+		"user:/tests/go/elektra/equal/1": {"type": "long"},
+	})
+
+	Assert(t, a.Equal(b, false), "KeySets should be equal without meta")
+	Assert(t, !a.Equal(b, true), "KeySets should differ in meta")
+
+	_ = b.LookupByName("user:/tests/go/elektra/equal/2").SetString("changed")
+	Assert(t, !a.Equal(b, false), "KeySets should differ in values")
+}
@@ -0,0 +1,67 @@
+package kdb
+
+// #include <kdb.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrKeyLocked    = errors.New("key is locked")
+	ErrKeySetFrozen = errors.New("keyset is frozen")
+)
+
+type KeyLockFlags uint
+
+const (
+	KEY_LOCK_NAME  KeyLockFlags = C.KEY_LOCK_NAME
+	KEY_LOCK_VALUE KeyLockFlags = C.KEY_LOCK_VALUE
+	KEY_LOCK_META  KeyLockFlags = C.KEY_LOCK_META
+	KEY_LOCK_ALL                = KEY_LOCK_NAME | KEY_LOCK_VALUE | KEY_LOCK_META
+)
+
+// Lock permanently prevents changes to the parts of the Key selected by
+// `flags`, a locked Key can not be unlocked. Keys in a KeySet always
+// have a locked name.
+func (k *CKey) Lock(flags KeyLockFlags) error {
+	if ret := C.keyLock(k.Ptr, C.elektraLockFlags(flags)); ret < 0 {
+		return errors.New("could not lock key")
+	}
+
+	return nil
+}
+
+// IsLocked checks if all parts of the Key selected by `flags` are locked.
+func (k *CKey) IsLocked(flags KeyLockFlags) bool {
+	return KeyLockFlags(C.keyIsLocked(k.Ptr, C.elektraLockFlags(flags))) == flags
+}
+
+// lockedError returns ErrKeyLocked if the failed change was
+// rejected because of `flags` being locked.
+func (k *CKey) lockedError(flags KeyLockFlags, message string) error {
+	if k.IsLocked(flags) {
+		return fmt.Errorf("%s: %w", message, ErrKeyLocked)
+	}
+
+	return errors.New(message)
+}
+
+// Freeze locks the names, values and meta Keys of all Keys in the KeySet,
+// so it can be shared without being modified. Afterwards the methods that
+// would add or remove Keys do nothing and KDB.Get fails with ErrKeySetFrozen.
+// Only this CKeySet is frozen, not other wrappers of the same `Ptr`.
+func (ks *CKeySet) Freeze() error {
+	ks.frozen = true
+
+	var err error
+
+	ks.forEach(func(k Key, _ int) {
+		if lockErr := k.Lock(KEY_LOCK_ALL); lockErr != nil && err == nil {
+			err = lockErr
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,192 @@
+package kdb
+
+// #include <kdb.h>
+// #include <stdlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+var ErrMetaNotFound = errors.New("meta key not found")
+
+// Comment is a comment of a Key as stored by the storage
+// plugins in the `comment/#` meta Keys.
+type Comment struct {
+	// Text is the comment without the comment start.
+	Text string
+	// Start is the character sequence that starts the comment, e.g. "#".
+	Start string
+	// Space is the number of spaces before the comment.
+	Space int
+}
+
+// HasMeta checks if the Key has the meta Key `name`.
+func (k *CKey) HasMeta(name string) bool {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	return C.keyGetMeta(k.Ptr, cName) != nil
+}
+
+// MetaInt returns the meta value `name` as integer.
+func (k *CKey) MetaInt(name string) (int64, error) {
+	if !k.HasMeta(name) {
+		return 0, fmt.Errorf("%w: %q", ErrMetaNotFound, name)
+	}
+
+	return strconv.ParseInt(k.Meta(name), 10, 64)
+}
+
+// MetaBool returns the meta value `name` as boolean, Elektra
+// stores booleans as "1" and "0".
+func (k *CKey) MetaBool(name string) (bool, error) {
+	if !k.HasMeta(name) {
+		return false, fmt.Errorf("%w: %q", ErrMetaNotFound, name)
+	}
+
+	return strconv.ParseBool(k.Meta(name))
+}
+
+// CopyMeta copies the meta Key `name` from `src` to this Key.
+// If `src` does not have the meta Key it is removed from this Key.
+func (k *CKey) CopyMeta(src Key, name string) error {
+	source, err := toCKey(src)
+
+	if err != nil {
+		return err
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	if ret := C.keyCopyMeta(k.Ptr, source.Ptr, cName); ret < 0 {
+		return k.lockedError(KEY_LOCK_META, "could not copy meta")
+	}
+
+	return nil
+}
+
+// CopyAllMeta copies all meta Keys from `src` to this Key.
+func (k *CKey) CopyAllMeta(src Key) error {
+	source, err := toCKey(src)
+
+	if err != nil {
+		return err
+	}
+
+	if ret := C.keyCopyAllMeta(k.Ptr, source.Ptr); ret < 0 {
+		return k.lockedError(KEY_LOCK_META, "could not copy meta")
+	}
+
+	return nil
+}
+
+// Comments returns the comments of the Key, the first one is the inline
+// comment (`comment/#0`), the others are the comments above the Key.
+func Comments(key Key) []Comment {
+	meta := key.MetaMap()
+	names := metaArrayNames(meta, "comment")
+	comments := make([]Comment, len(names))
+
+	for i, name := range names {
+		space, _ := strconv.Atoi(meta[name+"/space"])
+
+		comments[i] = Comment{
+			Text:  meta[name],
+			Start: meta[name+"/start"],
+			Space: space,
+		}
+	}
+
+	return comments
+}
+
+// SetComments replaces the comments of the Key, see Comments.
+func SetComments(key Key, comments []Comment) error {
+	for name := range key.MetaMap() {
+		if name == "comment" || strings.HasPrefix(name, "comment/") {
+			if err := key.RemoveMeta(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, comment := range comments {
+		name := "comment/" + ArrayIndex(i)
+
+		if err := key.SetMeta(name, comment.Text); err != nil {
+			return err
+		}
+
+		if comment.Start != "" {
+			if err := key.SetMeta(name+"/start", comment.Start); err != nil {
+				return err
+			}
+		}
+
+		if comment.Space > 0 {
+			if err := key.SetMeta(name+"/space", strconv.Itoa(comment.Space)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// metaArray returns the values of the meta array `name`, e.g.
+// `override/#0`, `override/#1`, ... in the order of their indices.
+func metaArray(key Key, name string) []string {
+	meta := key.MetaMap()
+	names := metaArrayNames(meta, name)
+	values := make([]string, len(names))
+
+	for i, n := range names {
+		values[i] = meta[n]
+	}
+
+	return values
+}
+
+// metaArrayNames returns the sorted names of the elements of the meta array `name`.
+func metaArrayNames(meta map[string]string, name string) []string {
+	prefix := name + "/#"
+
+	var names []string
+
+	for metaName := range meta {
+		if strings.HasPrefix(metaName, prefix) && !strings.Contains(metaName[len(prefix):], "/") {
+			names = append(names, metaName)
+		}
+	}
+
+	// Elektra array indices sort correctly as strings, e.g. #9 < #_10
+	sort.Strings(names)
+
+	return names
+}
+
+// ArrayIndex returns the Elektra array index `i`, e.g. #0, #9 or #_10.
+func ArrayIndex(i int) string {
+	digits := strconv.Itoa(i)
+
+	return "#" + strings.Repeat("_", len(digits)-1) + digits
+}
+
+// ParseArrayIndex parses an Elektra array index like #9 or #_10.
+func ParseArrayIndex(index string) (int, error) {
+	digits := strings.TrimLeft(strings.TrimPrefix(index, "#"), "_")
+	i, err := strconv.Atoi(digits)
+
+	if err != nil || i < 0 || index != ArrayIndex(i) {
+		return 0, fmt.Errorf("invalid array index %q", index)
+	}
+
+	return i, nil
+}
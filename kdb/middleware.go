@@ -0,0 +1,120 @@
+package kdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrReadOnly            = errors.New("kdb is read-only")
+	ErrParentKeyNotAllowed = errors.New("parent key not allowed")
+)
+
+// Middleware wraps a KDB to add behavior to its operations. It returns a KDB
+// that calls `next` for the operations it does not handle itself. Embedding
+// `next` in a struct and overriding single methods is the easiest way to
+// write one, see ReadOnly. Such a KDB only has the methods of the KDB
+// interface, a Middleware that should pass on warnings has to implement
+// WarningsKDB as well, like the Middlewares of this package do.
+type Middleware func(next KDB) KDB
+
+// Chain wraps `base` with the `middlewares`, the first middleware is the
+// outermost one and sees every call first.
+func Chain(base KDB, middlewares ...Middleware) KDB {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		base = middlewares[i](base)
+	}
+
+	return base
+}
+
+type readOnly struct {
+	KDB
+}
+
+// ReadOnly is a Middleware whose Set always fails with ErrReadOnly.
+func ReadOnly(next KDB) KDB {
+	return &readOnly{next}
+}
+
+func (r *readOnly) Set(keySet KeySet, parentKey Key) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (r *readOnly) GetWithWarnings(keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
+	return GetWithWarnings(r.KDB, keySet, parentKey)
+}
+
+func (r *readOnly) SetWithWarnings(keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
+	return false, nil, ErrReadOnly
+}
+
+type allowParentKeys struct {
+	KDB
+	allowed []Key
+}
+
+// AllowParentKeys returns a Middleware that rejects Get and Set with
+// ErrParentKeyNotAllowed unless the parent Key is below or the same
+// as one of the `allowed` Keys. A cascading parent Key is only allowed
+// below a cascading Key, as it reads and writes all namespaces.
+func AllowParentKeys(allowed ...Key) Middleware {
+	return func(next KDB) KDB {
+		return &allowParentKeys{next, allowed}
+	}
+}
+
+func (a *allowParentKeys) check(parentKey Key) error {
+	if belowOrSameAny(parentKey, a.allowed) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrParentKeyNotAllowed, parentKey.Name())
+}
+
+func (a *allowParentKeys) Get(keySet KeySet, parentKey Key) (bool, error) {
+	changed, _, err := a.GetWithWarnings(keySet, parentKey)
+
+	return changed, err
+}
+
+func (a *allowParentKeys) Set(keySet KeySet, parentKey Key) (bool, error) {
+	changed, _, err := a.SetWithWarnings(keySet, parentKey)
+
+	return changed, err
+}
+
+func (a *allowParentKeys) GetWithWarnings(keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
+	if err := a.check(parentKey); err != nil {
+		return false, nil, err
+	}
+
+	return GetWithWarnings(a.KDB, keySet, parentKey)
+}
+
+func (a *allowParentKeys) SetWithWarnings(keySet KeySet, parentKey Key) (bool, []ElektraWarning, error) {
+	if err := a.check(parentKey); err != nil {
+		return false, nil, err
+	}
+
+	return SetWithWarnings(a.KDB, keySet, parentKey)
+}
+
+// belowOrSameAny reports whether `k` is below or the same as one of `parents`.
+// Unlike IsBelowOrSame the namespaces have to match, unless the parent is
+// cascading, so a cascading `k` is only below cascading parents.
+func belowOrSameAny(k Key, parents []Key) bool {
+	for _, parent := range parents {
+		ns := parent.Namespace()
+
+		if ns != KEY_NS_CASCADING && ns != k.Namespace() {
+			continue
+		}
+
+		if k.IsBelowOrSame(parent) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+package kdb_test
+
+import (
+	"errors"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/kdb/kdbtest"
+	. "go.libelektra.org/test"
+)
+
+type recorder struct {
+	elektra.KDB
+	name  string
+	order *[]string
+}
+
+func (r *recorder) Get(keySet elektra.KeySet, parentKey elektra.Key) (bool, error) {
+	*r.order = append(*r.order, r.name)
+
+	return r.KDB.Get(keySet, parentKey)
+}
+
+func record(name string, order *[]string) elektra.Middleware {
+	return func(next elektra.KDB) elektra.KDB {
+		return &recorder{next, name, order}
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+
+	kdb := elektra.Chain(kdbtest.NewMock(t), record("first", &order), record("second", &order))
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/elektra/chain")
+	_, err := kdb.Get(elektra.NewKeySet(), parentKey)
+	Check(t, err, "Get failed")
+
+	Assertf(t, len(order) == 2 && order[0] == "first" && order[1] == "second", "wrong order %v", order)
+}
+
+func TestReadOnly(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	kdb := elektra.Chain(mock, elektra.ReadOnly)
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/elektra/readonly")
+
+	_, err := kdb.Get(elektra.NewKeySet(), parentKey)
+	Check(t, err, "Get should be allowed")
+
+	_, err = kdb.Set(elektra.NewKeySet(), parentKey)
+	Assert(t, errors.Is(err, elektra.ErrReadOnly), "Set should return ErrReadOnly")
+	Assertf(t, len(mock.Calls()) == 1, "Set should not reach the KDB, calls: %v", mock.Calls())
+}
+
+func TestAllowParentKeys(t *testing.T) {
+	allowed, _ := elektra.NewKey("user:/tests/go/elektra/allowed")
+	kdb := elektra.Chain(kdbtest.NewMock(t), elektra.AllowParentKeys(allowed))
+
+	below, _ := elektra.NewKey("user:/tests/go/elektra/allowed/sub")
+	_, err := kdb.Set(elektra.NewKeySet(), below)
+	Check(t, err, "Set below an allowed Key failed")
+
+	other, _ := elektra.NewKey("user:/tests/go/elektra/other")
+	_, err = kdb.Get(elektra.NewKeySet(), other)
+	Assert(t, errors.Is(err, elektra.ErrParentKeyNotAllowed), "Get of another parent should return ErrParentKeyNotAllowed")
+}
+
+func TestAllowParentKeysCascading(t *testing.T) {
+	allowed, _ := elektra.NewKey("user:/tests/go/elektra/allowed")
+	kdb := elektra.Chain(kdbtest.NewMock(t), elektra.AllowParentKeys(allowed))
+
+	cascading, _ := elektra.NewKey("/tests/go/elektra/allowed")
+	_, err := kdb.Get(elektra.NewKeySet(), cascading)
+	Assert(t, errors.Is(err, elektra.ErrParentKeyNotAllowed), "a cascading parent should not be allowed below a namespaced Key")
+
+	allowedCascading, _ := elektra.NewKey("/tests/go/elektra/allowed")
+	kdb = elektra.Chain(kdbtest.NewMock(t), elektra.AllowParentKeys(allowedCascading))
+
+	_, err = kdb.Get(elektra.NewKeySet(), cascading)
+	Check(t, err, "a cascading parent should be allowed below a cascading Key")
+
+	system, _ := elektra.NewKey("system:/tests/go/elektra/allowed/sub")
+	_, err = kdb.Get(elektra.NewKeySet(), system)
+	Check(t, err, "a namespaced parent should be allowed below a cascading Key")
+}
+
+func TestMiddlewareWarnings(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	mock.Warn("Get", elektra.ElektraWarning{ElektraError: *kdbtest.Error(elektra.ErrPluginMisbehavior, "deprecated key")})
+
+	allowed, _ := elektra.NewKey("user:/tests/go/elektra/allowed")
+	kdb := elektra.Chain(mock, elektra.ReadOnly, elektra.AllowParentKeys(allowed))
+
+	_, warnings, err := elektra.GetWithWarnings(kdb, elektra.NewKeySet(), allowed)
+	Check(t, err, "Get failed")
+	Assertf(t, len(warnings) == 1, "the warnings should be passed through the chain: %v", warnings)
+}
@@ -0,0 +1,127 @@
+package kdb
+
+// #include <kdb.h>
+import "C"
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidNamespace = errors.New("invalid namespace")
+
+var namespaceNames = map[ElektraNamespace]string{
+	KEY_NS_CASCADING: "",
+	KEY_NS_META:      "meta",
+	KEY_NS_SPEC:      "spec",
+	KEY_NS_PROC:      "proc",
+	KEY_NS_DIR:       "dir",
+	KEY_NS_USER:      "user",
+	KEY_NS_SYSTEM:    "system",
+	KEY_NS_DEFAULT:   "default",
+}
+
+// String returns the name of the namespace like it is used in
+// Key names, e.g. "user:". The cascading namespace is "/", so
+// use KeyName instead of concatenating String with a Key name.
+func (ns ElektraNamespace) String() string {
+	name, ok := namespaceNames[ns]
+
+	switch {
+	case !ok:
+		return "none"
+	case ns == KEY_NS_CASCADING:
+		return "/"
+	}
+
+	return name + ":"
+}
+
+// KeyName returns the cascading Key name `name`, e.g. "/sw/app",
+// in the namespace, e.g. "user:/sw/app". Cascading names are kept.
+func (ns ElektraNamespace) KeyName(name string) string {
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+
+	if ns == KEY_NS_CASCADING {
+		return name
+	}
+
+	return ns.String() + name
+}
+
+// ParseNamespace parses a namespace like "user", "user:" or "user:/".
+// "/" is the cascading namespace.
+func ParseNamespace(name string) (ElektraNamespace, error) {
+	if name == "/" {
+		return KEY_NS_CASCADING, nil
+	}
+
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "/"), ":")
+
+	for ns, n := range namespaceNames {
+		if n != "" && n == name {
+			return ns, nil
+		}
+	}
+
+	return KEY_NS_NONE, ErrInvalidNamespace
+}
+
+// Namespaces returns the namespaces Keys can be stored in, in the order
+// a cascading lookup considers them: spec:/ (which holds the overrides),
+// proc:/, dir:/, user:/, system:/ and finally default:/.
+func Namespaces() []ElektraNamespace {
+	return []ElektraNamespace{
+		KEY_NS_SPEC,
+		KEY_NS_PROC,
+		KEY_NS_DIR,
+		KEY_NS_USER,
+		KEY_NS_SYSTEM,
+		KEY_NS_DEFAULT,
+	}
+}
+
+// SetNamespace moves the Key to the namespace `ns`, the rest of the name is kept.
+func (k *CKey) SetNamespace(ns ElektraNamespace) error {
+	if _, ok := namespaceNames[ns]; !ok {
+		return ErrInvalidNamespace
+	}
+
+	if ret := C.keySetNamespace(k.Ptr, C.elektraNamespace(ns)); ret < 0 {
+		return k.lockedError(KEY_LOCK_NAME, "could not set namespace")
+	}
+
+	return nil
+}
+
+// WithNamespace returns a duplicate of the Key in the namespace `ns`
+// or nil if the namespace is invalid.
+func (k *CKey) WithNamespace(ns ElektraNamespace) Key {
+	dup := wrapKey(C.keyDup(k.Ptr, C.KEY_CP_ALL))
+
+	if dup == nil {
+		return nil
+	}
+
+	if err := dup.SetNamespace(ns); err != nil {
+		dup.Close()
+		return nil
+	}
+
+	return dup
+}
+
+// ByNamespace returns a new KeySet with the Keys of the namespace `ns`.
+func (ks *CKeySet) ByNamespace(ns ElektraNamespace) KeySet {
+	result := NewKeySet()
+
+	ks.forEach(func(k Key, _ int) {
+		if k.Namespace() == ns {
+			result.AppendKey(k)
+		}
+	})
+
+	return result
+}
@@ -0,0 +1,230 @@
+package kdb
+
+// #cgo LDFLAGS: -ldl -lelektra-invoke
+// #define _GNU_SOURCE
+// #include <dlfcn.h>
+// #include <stdlib.h>
+// #include <kdb.h>
+// #include <kdbinvoke.h>
+//
+// static const char * elektraLibraryPath(void) {
+//   Dl_info info;
+//
+//   if (dladdr((void *) keyNew, &info) == 0) {
+//     return NULL;
+//   }
+//
+//   return info.dli_fname;
+// }
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+const modulesKeyName = "system:/elektra/modules"
+
+var ErrPluginNotFound = errors.New("plugin not found")
+
+// PluginContract describes what a plugin provides and needs,
+// as declared in its contract.
+type PluginContract struct {
+	Name        string
+	Provides    []string
+	Needs       []string
+	Recommends  []string
+	Placements  []string
+	Status      []string
+	Description string
+	Author      string
+	Licence     string
+	// Config contains the configuration the plugin needs
+	// from the backend it is mounted in.
+	Config map[string]string
+}
+
+// ListPlugins returns the sorted names of the plugins Elektra knows: the
+// plugins installed next to the Elektra library and those listed below
+// "system:/elektra/modules". The latter only lists plugins that are already
+// loaded, which includes plugins built into libelektra.
+func ListPlugins() ([]string, error) {
+	loaded, err := loadedPlugins()
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+
+	for _, name := range append(installedPlugins(), loaded...) {
+		names[name] = true
+	}
+
+	plugins := make([]string, 0, len(names))
+
+	for name := range names {
+		plugins = append(plugins, name)
+	}
+
+	sort.Strings(plugins)
+
+	return plugins, nil
+}
+
+// loadedPlugins returns the names of the plugins listed
+// below "system:/elektra/modules".
+func loadedPlugins() ([]string, error) {
+	handle := &KdbC{}
+
+	if err := handle.Open(); err != nil {
+		return nil, err
+	}
+
+	defer handle.Close()
+
+	parentKey, err := newKey(modulesKeyName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer parentKey.Close()
+
+	ks := NewKeySet()
+	defer ks.Close()
+
+	if _, err = handle.Get(ks, parentKey); err != nil {
+		return nil, err
+	}
+
+	var plugins []string
+
+	ks.ForEach(func(k Key, _ int) {
+		if k.IsDirectlyBelow(parentKey) {
+			plugins = append(plugins, k.BaseName())
+		}
+	})
+
+	return plugins, nil
+}
+
+// installedPlugins returns the names of the plugin libraries in the
+// plugin directory next to the Elektra library, e.g. "elektra/libelektra-dump.so".
+func installedPlugins() []string {
+	dir := libraryDir()
+
+	if dir == "" {
+		return nil
+	}
+
+	var plugins []string
+
+	for _, ext := range []string{".so", ".dylib"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, "elektra*", "libelektra-*"+ext))
+
+		for _, match := range matches {
+			name := strings.TrimPrefix(filepath.Base(match), "libelektra-")
+			plugins = append(plugins, strings.TrimSuffix(name, ext))
+		}
+	}
+
+	return plugins
+}
+
+// libraryDir returns the directory of the Elektra library this package
+// is linked against or an empty string if it is unknown.
+func libraryDir() string {
+	libPath := C.elektraLibraryPath()
+
+	if libPath == nil {
+		return ""
+	}
+
+	return filepath.Dir(C.GoString(libPath))
+}
+
+// PluginInfo loads the plugin `name` and returns its contract. The plugin
+// does not have to be mounted or loaded already.
+func PluginInfo(name string) (*PluginContract, error) {
+	errorKey, err := newKey(modulesKeyName + "/" + EscapeBaseName(name))
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin name %q: %w", name, err)
+	}
+
+	defer errorKey.Close()
+
+	config := NewKeySet().(*CKeySet)
+	defer config.Close()
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	handle := C.elektraInvokeOpen(cName, config.Ptr, errorKey.Ptr)
+
+	if handle == nil {
+		if errorKey.Meta("error/number") != "" {
+			return nil, fmt.Errorf("%w: %q: %w", ErrPluginNotFound, name, errFromKey(errorKey))
+		}
+
+		return nil, fmt.Errorf("%w: %q", ErrPluginNotFound, name)
+	}
+
+	defer C.elektraInvokeClose(handle, errorKey.Ptr)
+
+	ks := wrapKeySet(C.elektraInvokeGetExports(handle))
+
+	if ks == nil {
+		return nil, fmt.Errorf("%w: %q", ErrPluginNotFound, name)
+	}
+
+	defer ks.Close()
+
+	return contractFromKeySet(ks, name)
+}
+
+// contractFromKeySet builds the contract of the plugin `name`
+// from the Keys below "system:/elektra/modules/<name>".
+func contractFromKeySet(ks KeySet, name string) (*PluginContract, error) {
+	root := modulesKeyName + "/" + EscapeBaseName(name)
+
+	if ks.LookupByName(root) == nil {
+		return nil, fmt.Errorf("%w: %q", ErrPluginNotFound, name)
+	}
+
+	value := func(info string) string {
+		if k := ks.LookupByName(root + "/infos/" + info); k != nil {
+			return k.String()
+		}
+
+		return ""
+	}
+
+	contract := &PluginContract{
+		Name:        name,
+		Provides:    strings.Fields(value("provides")),
+		Needs:       strings.Fields(value("needs")),
+		Recommends:  strings.Fields(value("recommends")),
+		Placements:  strings.Fields(value("placements")),
+		Status:      strings.Fields(value("status")),
+		Description: value("description"),
+		Author:      value("author"),
+		Licence:     value("licence"),
+		Config:      map[string]string{},
+	}
+
+	configPrefix := root + "/config/needs/"
+
+	ks.ForEach(func(k Key, _ int) {
+		if strings.HasPrefix(k.Name(), configPrefix) {
+			contract.Config[strings.TrimPrefix(k.Name(), configPrefix)] = k.String()
+		}
+	})
+
+	return contract, nil
+}
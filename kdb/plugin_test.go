@@ -0,0 +1,84 @@
+package kdb
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	. "go.libelektra.org/test"
+)
+
+func TestContractFromKeySet(t *testing.T) {
+	ks := NewKeySet()
+	defer ks.Close()
+
+	for name, value := range map[string]string{
+		"system:/elektra/modules/dump":                        "dump plugin waits for your orders",
+		"system:/elektra/modules/dump/infos/provides":         "storage/dump",
+		"system:/elektra/modules/dump/infos/placements":       "getstorage setstorage",
+		"system:/elektra/modules/dump/infos/status":           "maintained unittest",
+		"system:/elektra/modules/dump/infos/description":      "Dumps complete Elektra Semantics",
+		"system:/elektra/modules/dump/config/needs/format":    "binary",
+		"system:/elektra/modules/other/infos/placements":      "postgetstorage",
+		"system:/elektra/modules/dump/exports/get":            "",
+		"system:/elektra/modules/dump/infos/licence":          "BSD",
+		"system:/elektra/modules/dump/infos/author":           "Markus Raab <elektra@libelektra.org>",
+		"system:/elektra/modules/dump/infos/needs":            "",
+		"system:/elektra/modules/dump/infos/recommends":       "",
+		"system:/elektra/modules/dump/config/needs/separator": ";",
+	} {
+		k, err := NewKey(name, value)
+		Checkf(t, err, "could not create key %q: %v", name, err)
+		ks.AppendKey(k)
+	}
+
+	contract, err := contractFromKeySet(ks, "dump")
+	Check(t, err, "could not read contract")
+
+	Assert(t, reflect.DeepEqual(contract.Provides, []string{"storage/dump"}), "wrong provides")
+	Assert(t, reflect.DeepEqual(contract.Placements, []string{"getstorage", "setstorage"}), "wrong placements")
+	Assert(t, reflect.DeepEqual(contract.Status, []string{"maintained", "unittest"}), "wrong status")
+	Assert(t, len(contract.Needs) == 0, "needs should be empty")
+	Assertf(t, contract.Description == "Dumps complete Elektra Semantics", "wrong description %q", contract.Description)
+	Assert(t, reflect.DeepEqual(contract.Config, map[string]string{"format": "binary", "separator": ";"}), "wrong config")
+
+	_, err = contractFromKeySet(ks, "other")
+	Assert(t, errors.Is(err, ErrPluginNotFound), "plugin without root key should not be found")
+}
+
+func TestListPlugins(t *testing.T) {
+	plugins, err := ListPlugins()
+	Check(t, err, "ListPlugins failed")
+
+	Assertf(t, sort.StringsAreSorted(plugins), "plugins should be sorted: %v", plugins)
+
+	i := sort.SearchStrings(plugins, "dump")
+	Assertf(t, i < len(plugins) && plugins[i] == "dump", "the dump plugin should be listed: %v", plugins)
+}
+
+func TestPluginInfoNotLoaded(t *testing.T) {
+	loaded, err := loadedPlugins()
+	Check(t, err, "could not list loaded plugins")
+
+	sort.Strings(loaded)
+
+	for _, name := range installedPlugins() {
+		if i := sort.SearchStrings(loaded, name); i < len(loaded) && loaded[i] == name {
+			continue
+		}
+
+		contract, err := PluginInfo(name)
+		Checkf(t, err, "PluginInfo of the unloaded plugin %q failed: %v", name, err)
+		Assertf(t, contract.Name == name, "wrong contract name %q", contract.Name)
+
+		return
+	}
+
+	t.Skip("no installed plugin that is not loaded")
+}
+
+func TestPluginInfoNotFound(t *testing.T) {
+	_, err := PluginInfo("doesnotexist")
+	Assert(t, errors.Is(err, ErrPluginNotFound), "PluginInfo of a missing plugin should return ErrPluginNotFound")
+}
@@ -0,0 +1,194 @@
+package kdb
+
+import "fmt"
+
+// cascadingNamespaces are the namespaces a cascading lookup
+// searches, in the order they are searched.
+var cascadingNamespaces = []ElektraNamespace{
+	KEY_NS_PROC,
+	KEY_NS_DIR,
+	KEY_NS_USER,
+	KEY_NS_SYSTEM,
+}
+
+// Resolve looks up the cascading Key `name` like Elektra's spec plugin does:
+// the `override/#` links of the spec:/ Key are followed first, then the
+// namespaces proc:/, dir:/, user:/ and system:/ are searched (or the ones
+// listed in the `namespace/#` meta Keys), then the `fallback/#` links are
+// followed and finally the `default` meta Key is used. A Key created from
+// the `default` meta Key is appended to this KeySet in the default:/ namespace.
+// Returns the Key, the namespace it was found in and ErrKeyNotFound if none was found.
+func (ks *CKeySet) Resolve(name string) (Key, ElektraNamespace, error) {
+	key, ns, _, err := ResolveExplain(ks, name)
+
+	return key, ns, err
+}
+
+// ResolveExplain works like KeySet.Resolve but additionally returns a
+// trace of every step that was taken, which is useful for debugging
+// why a certain Key was chosen.
+func ResolveExplain(ks KeySet, name string) (Key, ElektraNamespace, []string, error) {
+	k, err := newKey(name)
+
+	if err != nil {
+		return nil, KEY_NS_NONE, nil, err
+	}
+
+	defer k.Close()
+
+	r := &resolver{ks: ks, visited: map[string]bool{}}
+
+	var key Key
+	var ns ElektraNamespace
+
+	if k.Namespace() == KEY_NS_CASCADING {
+		key, ns = r.resolve(k.Name())
+	} else {
+		key, ns = r.lookup(k.Name(), k.Namespace())
+	}
+
+	if key == nil {
+		return nil, KEY_NS_NONE, r.trace, fmt.Errorf("%w: %q", ErrKeyNotFound, name)
+	}
+
+	return key, ns, r.trace, nil
+}
+
+type resolver struct {
+	ks      KeySet
+	trace   []string
+	visited map[string]bool
+}
+
+func (r *resolver) tracef(format string, args ...interface{}) {
+	r.trace = append(r.trace, fmt.Sprintf(format, args...))
+}
+
+func (r *resolver) lookup(name string, ns ElektraNamespace) (Key, ElektraNamespace) {
+	if key := r.ks.LookupByName(name); key != nil {
+		r.tracef("%s: found", name)
+		return key, ns
+	}
+
+	r.tracef("%s: not found", name)
+
+	return nil, KEY_NS_NONE
+}
+
+// resolve resolves the cascading name `path`.
+func (r *resolver) resolve(path string) (Key, ElektraNamespace) {
+	if r.visited[path] {
+		r.tracef("%s: already visited, skipping to avoid a cycle", path)
+		return nil, KEY_NS_NONE
+	}
+
+	r.visited[path] = true
+
+	specName := KEY_NS_SPEC.KeyName(path)
+	spec := r.ks.LookupByName(specName)
+
+	if spec == nil {
+		r.tracef("%s: no spec key", specName)
+	}
+
+	if key, ns := r.follow(spec, specName, "override"); key != nil {
+		return key, ns
+	}
+
+	namespaces := cascadingNamespaces
+
+	if spec != nil {
+		if names := metaArray(spec, "namespace"); len(names) > 0 {
+			namespaces = nil
+
+			for _, n := range names {
+				ns, err := ParseNamespace(n)
+
+				if err != nil {
+					r.tracef("%s: ignoring unknown namespace %q", specName, n)
+					continue
+				}
+
+				namespaces = append(namespaces, ns)
+			}
+
+			r.tracef("%s: restricted namespaces to %v", specName, names)
+		}
+	}
+
+	for _, ns := range namespaces {
+		if key, found := r.lookup(ns.KeyName(path), ns); key != nil {
+			return key, found
+		}
+	}
+
+	if key, ns := r.follow(spec, specName, "fallback"); key != nil {
+		return key, ns
+	}
+
+	defaultName := KEY_NS_DEFAULT.KeyName(path)
+
+	if key := r.ks.LookupByName(defaultName); key != nil {
+		r.tracef("%s: found", defaultName)
+		return key, KEY_NS_DEFAULT
+	}
+
+	if spec == nil {
+		return nil, KEY_NS_NONE
+	}
+
+	value, ok := spec.MetaMap()["default"]
+
+	if !ok {
+		r.tracef("%s: no default", specName)
+		return nil, KEY_NS_NONE
+	}
+
+	key, err := newKey(defaultName, value)
+
+	if err != nil {
+		r.tracef("%s: could not create default key: %v", specName, err)
+		return nil, KEY_NS_NONE
+	}
+
+	r.ks.AppendKey(key)
+	r.tracef("%s: using default %q", specName, value)
+
+	return key, KEY_NS_DEFAULT
+}
+
+// follow resolves the links stored in the `override/#` or
+// `fallback/#` meta Keys of `spec` until one of them is found.
+func (r *resolver) follow(spec Key, specName, kind string) (Key, ElektraNamespace) {
+	if spec == nil {
+		return nil, KEY_NS_NONE
+	}
+
+	for _, link := range metaArray(spec, kind) {
+		r.tracef("%s: following %s %s", specName, kind, link)
+
+		k, err := newKey(link)
+
+		if err != nil {
+			r.tracef("%s: invalid %s %q", specName, kind, link)
+			continue
+		}
+
+		var key Key
+		var ns ElektraNamespace
+
+		if k.Namespace() == KEY_NS_CASCADING {
+			key, ns = r.resolve(k.Name())
+		} else {
+			key, ns = r.lookup(k.Name(), k.Namespace())
+		}
+
+		k.Close()
+
+		if key != nil {
+			return key, ns
+		}
+	}
+
+	return nil, KEY_NS_NONE
+}
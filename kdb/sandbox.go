@@ -0,0 +1,72 @@
+package kdb
+
+// #include <kdb.h>
+import "C"
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+// PermissionDeniedError is returned by Set of a sandboxed KDB if the
+// parent Key or changed Keys are outside of the allowed subtrees.
+type PermissionDeniedError struct {
+	// Keys are the names of the offending Keys.
+	Keys []string
+}
+
+func (e *PermissionDeniedError) Error() string {
+	return "permission denied, keys outside of the allowed subtrees: " + strings.Join(e.Keys, ", ")
+}
+
+// Is reports whether `target` is ErrPermissionDenied.
+func (e *PermissionDeniedError) Is(target error) bool {
+	return target == ErrPermissionDenied
+}
+
+// WithSandbox restricts Set to the subtrees of the `allowed` Keys, Set
+// fails with a PermissionDeniedError before anything is written otherwise.
+// The parent Key of Set has to be below or the same as one of the `allowed`
+// Keys, so Keys removed from the KeySet can only be deleted inside of the
+// sandbox. Cascading parent Keys write all namespaces and are only allowed
+// below cascading `allowed` Keys.
+func WithSandbox(allowed ...Key) Option {
+	return func(e *KdbC) {
+		e.sandbox = append([]Key{}, allowed...)
+		e.sandboxed = true
+	}
+}
+
+// checkSandbox returns a PermissionDeniedError if `parentKey`
+// or a changed Key of `ks` is outside of the sandbox.
+func (e *KdbC) checkSandbox(ks *CKeySet, parentKey *CKey) error {
+	if !e.sandboxed {
+		return nil
+	}
+
+	if !belowOrSameAny(parentKey, e.sandbox) {
+		return &PermissionDeniedError{Keys: []string{parentKey.Name()}}
+	}
+
+	var denied []string
+
+	ks.forEach(func(k Key, _ int) {
+		ck, err := toCKey(k)
+
+		if err != nil || C.keyNeedSync(ck.Ptr) != 1 {
+			return
+		}
+
+		if !belowOrSameAny(k, e.sandbox) {
+			denied = append(denied, k.Name())
+		}
+	})
+
+	if len(denied) > 0 {
+		return &PermissionDeniedError{Keys: denied}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package kdb_test
+
+import (
+	"errors"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	. "go.libelektra.org/test"
+)
+
+func TestSandbox(t *testing.T) {
+	allowed, _ := elektra.NewKey("user:/tests/go/elektra/sandbox/allowed")
+	kdb := elektra.New(elektra.WithSandbox(allowed))
+
+	err := kdb.Open()
+	Check(t, err, "could not open KDB")
+	defer kdb.Close()
+
+	ks := elektra.NewKeySet()
+	defer ks.Close()
+
+	_, err = kdb.Get(ks, allowed)
+	Check(t, err, "could not Get")
+
+	inside, _ := elektra.NewKey("user:/tests/go/elektra/sandbox/allowed/key", "value")
+	outside, _ := elektra.NewKey("user:/tests/go/elektra/sandbox/other", "value")
+	ks.AppendKey(inside)
+	ks.AppendKey(outside)
+
+	_, err = kdb.Set(ks, allowed)
+	Assert(t, errors.Is(err, elektra.ErrPermissionDenied), "Set outside of the sandbox should return ErrPermissionDenied")
+
+	var denied *elektra.PermissionDeniedError
+	Assert(t, errors.As(err, &denied), "error should be a PermissionDeniedError")
+	Assertf(t, len(denied.Keys) == 1 && denied.Keys[0] == outside.Name(), "wrong offending Keys %v", denied.Keys)
+
+	ks.Remove(outside).Close()
+
+	_, err = kdb.Set(ks, allowed)
+	Check(t, err, "Set inside of the sandbox failed")
+}
+
+func TestSandboxParentKey(t *testing.T) {
+	allowed, _ := elektra.NewKey("user:/tests/go/elektra/sandbox/allowed")
+	kdb := elektra.New(elektra.WithSandbox(allowed))
+
+	err := kdb.Open()
+	Check(t, err, "could not open KDB")
+	defer kdb.Close()
+
+	for _, name := range []string{"user:/", "user:/tests/go/elektra/sandbox", "/tests/go/elektra/sandbox/allowed", "system:/tests/go/elektra/sandbox/allowed"} {
+		parentKey, _ := elektra.NewKey(name)
+
+		_, err = kdb.Set(elektra.NewKeySet(), parentKey)
+		Assertf(t, errors.Is(err, elektra.ErrPermissionDenied), "Set with parent Key %q should return ErrPermissionDenied", name)
+	}
+}
+
+func TestSandboxRemoveOutside(t *testing.T) {
+	parentKey, _ := elektra.NewKey("user:/tests/go/elektra/sandbox")
+	outside, _ := elektra.NewKey("user:/tests/go/elektra/sandbox/other", "value")
+
+	setKeys(t, parentKey, outside)
+	defer setKeys(t, parentKey)
+
+	allowed, _ := elektra.NewKey("user:/tests/go/elektra/sandbox/allowed")
+	kdb := elektra.New(elektra.WithSandbox(allowed))
+
+	err := kdb.Open()
+	Check(t, err, "could not open KDB")
+	defer kdb.Close()
+
+	ks := elektra.NewKeySet()
+	defer ks.Close()
+
+	_, err = kdb.Get(ks, parentKey)
+	Check(t, err, "could not Get")
+
+	ks.RemoveByName(outside.Name()).Close()
+
+	_, err = kdb.Set(ks, parentKey)
+	Assert(t, errors.Is(err, elektra.ErrPermissionDenied), "deleting a Key outside of the sandbox should return ErrPermissionDenied")
+}
+
+// setKeys replaces the Keys below `parentKey` with `keys`.
+func setKeys(t *testing.T, parentKey elektra.Key, keys ...elektra.Key) {
+	t.Helper()
+
+	handle := elektra.New()
+
+	err := handle.Open()
+	Check(t, err, "could not open KDB")
+	defer handle.Close()
+
+	ks := elektra.NewKeySet()
+	defer ks.Close()
+
+	_, err = handle.Get(ks, parentKey)
+	Check(t, err, "could not Get")
+
+	ks.Cut(parentKey).Close()
+
+	for _, k := range keys {
+		ks.AppendKey(k.Duplicate(elektra.KEY_CP_ALL))
+	}
+
+	_, err = handle.Set(ks, parentKey)
+	Check(t, err, "could not Set")
+}
@@ -0,0 +1,147 @@
+package kdb
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// UnionPolicy decides how Union handles Keys that are in both KeySets.
+type UnionPolicy uint
+
+const (
+	// PreferFirst keeps the Key of the first KeySet.
+	PreferFirst UnionPolicy = 0
+	// PreferSecond keeps the Key of the second KeySet.
+	PreferSecond UnionPolicy = 1 << 0
+	// MergeMeta adds the meta Keys of the other Key that the kept Key
+	// does not have. The kept Key is copied, so neither input is modified.
+	MergeMeta UnionPolicy = 1 << 1
+)
+
+// Union returns a new KeySet with the Keys of `a` and `b`, `policy`
+// decides which Key is kept if both contain a Key with the same name.
+// The Keys are shared with `a` and `b` unless meta Keys are merged.
+func Union(a, b KeySet, policy UnionPolicy) (KeySet, error) {
+	result := NewKeySet()
+	aKeys, bKeys := a.ToSlice(), b.ToSlice()
+	i, j := 0, 0
+
+	for i < len(aKeys) && j < len(bKeys) {
+		switch cmp := aKeys[i].Compare(bKeys[j]); {
+		case cmp < 0:
+			result.AppendKey(aKeys[i])
+			i++
+		case cmp > 0:
+			result.AppendKey(bKeys[j])
+			j++
+		default:
+			kept, other := aKeys[i], bKeys[j]
+
+			if policy&PreferSecond != 0 {
+				kept, other = other, kept
+			}
+
+			if policy&MergeMeta != 0 {
+				merged, err := mergeMeta(kept, other)
+
+				if err != nil {
+					result.Close()
+					return nil, err
+				}
+
+				kept = merged
+			}
+
+			result.AppendKey(kept)
+			i++
+			j++
+		}
+	}
+
+	for ; i < len(aKeys); i++ {
+		result.AppendKey(aKeys[i])
+	}
+
+	for ; j < len(bKeys); j++ {
+		result.AppendKey(bKeys[j])
+	}
+
+	return result, nil
+}
+
+// mergeMeta returns a copy of `kept` with the meta Keys of `other` it does not have.
+func mergeMeta(kept, other Key) (Key, error) {
+	merged := kept.Duplicate(KEY_CP_ALL)
+	meta := kept.MetaMap()
+
+	for name, value := range other.MetaMap() {
+		if _, ok := meta[name]; ok {
+			continue
+		}
+
+		if err := merged.SetMeta(name, value); err != nil {
+			merged.Close()
+			return nil, err
+		}
+	}
+
+	return merged, nil
+}
+
+// Intersect returns a new KeySet with the Keys of `a` that have a Key
+// with the same name in `b`.
+func Intersect(a, b KeySet) KeySet {
+	return filterByNames(a, b, true)
+}
+
+// Subtract returns a new KeySet with the Keys of `a` that do not have a Key
+// with the same name in `b`.
+func Subtract(a, b KeySet) KeySet {
+	return filterByNames(a, b, false)
+}
+
+// filterByNames walks both sorted KeySets and keeps the Keys of `a`
+// whose existence in `b` equals `inB`.
+func filterByNames(a, b KeySet, inB bool) KeySet {
+	result := NewKeySet()
+	aKeys, bKeys := a.ToSlice(), b.ToSlice()
+	j := 0
+
+	for _, k := range aKeys {
+		for j < len(bKeys) && bKeys[j].Compare(k) < 0 {
+			j++
+		}
+
+		found := j < len(bKeys) && bKeys[j].Compare(k) == 0
+
+		if found == inB {
+			result.AppendKey(k)
+		}
+	}
+
+	return result
+}
+
+// Equal checks if both KeySets contain Keys with the same names and
+// values, if `compareMeta` is true the meta Keys have to be equal too.
+func (ks *CKeySet) Equal(other KeySet, compareMeta bool) bool {
+	if ks.Len() != other.Len() {
+		return false
+	}
+
+	otherKeys := other.ToSlice()
+
+	for i, k := range ks.ToSlice() {
+		o := otherKeys[i]
+
+		if k.Compare(o) != 0 || k.String() != o.String() || !bytes.Equal(k.Bytes(), o.Bytes()) {
+			return false
+		}
+
+		if compareMeta && !reflect.DeepEqual(k.MetaMap(), o.MetaMap()) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,245 @@
+package kdb
+
+// #include <kdb.h>
+import "C"
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrCascadingSource = errors.New("cascading source needs a cascading destination")
+)
+
+// CopyTree copies `src` and all Keys below it to the corresponding names
+// below `dst`, values and meta Keys are preserved. Existing Keys below `dst`
+// with the same name are replaced.
+// If `dst` is cascading the copies keep the namespace of their source,
+// a cascading `src` with a namespaced `dst` fails with ErrCascadingSource
+// as Keys of different namespaces would be merged.
+func CopyTree(ks KeySet, src, dst Key) error {
+	_, copies, err := copyTree(ks, src, dst)
+
+	if err != nil {
+		return err
+	}
+
+	for _, c := range copies {
+		ks.AppendKey(c)
+	}
+
+	return nil
+}
+
+// MoveTree moves `src` and all Keys below it to the corresponding names
+// below `dst`, see CopyTree. `dst` may be below `src`, the Keys
+// are removed before their copies are added. The removed Keys are freed,
+// except `src` and `dst`, so other Keys of `ks` below `src` must not be
+// used afterwards.
+func MoveTree(ks KeySet, src, dst Key) error {
+	if src.Compare(dst) == 0 {
+		return nil
+	}
+
+	names, copies, err := copyTree(ks, src, dst)
+
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		closeRemoved(ks.RemoveByName(name), src, dst)
+	}
+
+	for _, c := range copies {
+		ks.AppendKey(c)
+	}
+
+	return nil
+}
+
+// RemoveTree removes `src` and all Keys below it from the KeySet. The removed
+// Keys are freed, except `src`, so other Keys of `ks` below `src` must not
+// be used afterwards.
+func RemoveTree(ks KeySet, src Key) error {
+	sources := keysBelowOrSame(ks, src)
+
+	if len(sources) == 0 {
+		return ErrKeyNotFound
+	}
+
+	for _, k := range sources {
+		closeRemoved(ks.Remove(k), src)
+	}
+
+	return nil
+}
+
+// CopyTreeKDB `Get`s the Keys below `src` and `dst`, copies them via
+// CopyTree and `Set`s the result, `src` and `dst` may be in different mountpoints.
+func CopyTreeKDB(handle KDB, src, dst Key) error {
+	return modifyTrees(handle, src, dst, false, func(ks KeySet) error {
+		return CopyTree(ks, src, dst)
+	})
+}
+
+// MoveTreeKDB `Get`s the Keys below `src` and `dst`, moves them via
+// MoveTree and `Set`s the result, `src` and `dst` may be in different mountpoints.
+func MoveTreeKDB(handle KDB, src, dst Key) error {
+	return modifyTrees(handle, src, dst, true, func(ks KeySet) error {
+		return MoveTree(ks, src, dst)
+	})
+}
+
+// RemoveTreeKDB `Get`s the Keys below `src`, removes them and `Set`s the result.
+func RemoveTreeKDB(handle KDB, src Key) error {
+	return modifyTrees(handle, src, src, true, func(ks KeySet) error {
+		return RemoveTree(ks, src)
+	})
+}
+
+// modifyTrees `Get`s both parents, calls `modify` and `Set`s the parents again.
+// If one parent contains the other a single Set is enough, otherwise `dst`
+// is written first, so a failure never loses the Keys of `src`.
+func modifyTrees(handle KDB, src, dst Key, setSrc bool, modify func(ks KeySet) error) error {
+	ks := NewKeySet()
+	defer ks.Close()
+
+	if _, err := handle.Get(ks, src); err != nil {
+		return err
+	}
+
+	if _, err := handle.Get(ks, dst); err != nil {
+		return err
+	}
+
+	if err := modify(ks); err != nil {
+		return err
+	}
+
+	switch {
+	case dst.IsBelowOrSame(src):
+		_, err := handle.Set(ks, src)
+		return err
+	case src.IsBelow(dst):
+		_, err := handle.Set(ks, dst)
+		return err
+	}
+
+	if _, err := handle.Set(ks, dst); err != nil || !setSrc {
+		return err
+	}
+
+	_, err := handle.Set(ks, src)
+
+	return err
+}
+
+// copyTree copies the Keys below `src` without adding the copies to `ks`,
+// it returns the names of the source Keys and the copies.
+func copyTree(ks KeySet, src, dst Key) ([]string, []*CKey, error) {
+	if src.Namespace() == KEY_NS_CASCADING && dst.Namespace() != KEY_NS_CASCADING {
+		return nil, nil, ErrCascadingSource
+	}
+
+	sources := keysBelowOrSame(ks, src)
+
+	if len(sources) == 0 {
+		return nil, nil, ErrKeyNotFound
+	}
+
+	names := make([]string, 0, len(sources))
+	copies := make([]*CKey, 0, len(sources))
+
+	for _, k := range sources {
+		copied, err := copyKey(k, treeName(k, src, dst))
+
+		if err != nil {
+			for _, c := range copies {
+				c.Close()
+			}
+
+			return nil, nil, err
+		}
+
+		names = append(names, k.Name())
+		copies = append(copies, copied)
+	}
+
+	return names, copies, nil
+}
+
+// closeRemoved frees the `removed` Key unless it is one of the Keys
+// in `keep`, which the caller passed and may still use.
+func closeRemoved(removed Key, keep ...Key) {
+	r, err := toCKey(removed)
+
+	if err != nil {
+		return
+	}
+
+	for _, k := range keep {
+		if c, err := toCKey(k); err == nil && c.Ptr == r.Ptr {
+			return
+		}
+	}
+
+	r.Close()
+}
+
+// keysBelowOrSame collects the Keys first, so they
+// can safely be modified afterwards.
+func keysBelowOrSame(ks KeySet, parent Key) []Key {
+	var keys []Key
+
+	ks.ForEach(func(k Key, _ int) {
+		if k.IsBelowOrSame(parent) {
+			keys = append(keys, k)
+		}
+	})
+
+	return keys
+}
+
+// treeName returns the name of `k` if it is moved from below `src` to below `dst`.
+func treeName(k, src, dst Key) string {
+	rel := strings.TrimPrefix(nameWithoutNamespace(k), nameWithoutNamespace(src))
+	rel = strings.TrimPrefix(rel, "/")
+
+	name := dst.Name()
+
+	if dst.Namespace() == KEY_NS_CASCADING && k.Namespace() != KEY_NS_CASCADING {
+		kName := k.Name()
+		name = kName[:strings.Index(kName, "/")] + name
+	}
+
+	if rel == "" {
+		return name
+	}
+
+	return strings.TrimSuffix(name, "/") + "/" + rel
+}
+
+// copyKey creates a new Key called `name` with the value and meta Keys of `k`.
+func copyKey(k Key, name string) (*CKey, error) {
+	source, err := toCKey(k)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dest, err := newKey(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if C.keyCopy(dest.Ptr, source.Ptr, C.KEY_CP_VALUE|C.KEY_CP_META) == nil {
+		dest.Close()
+		return nil, errors.New("could not copy key")
+	}
+
+	return dest, nil
+}
@@ -0,0 +1,155 @@
+package kdb_test
+
+import (
+	"errors"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	. "go.libelektra.org/test"
+)
+
+func treeKeySet(t *testing.T, names ...string) elektra.KeySet {
+	t.Helper()
+
+	ks := elektra.NewKeySet()
+
+	for _, name := range names {
+		k, err := elektra.NewKey(name, "value of "+name)
+		Check(t, err, "could not create key")
+
+		err = k.SetMeta("meta", name)
+		Check(t, err, "could not set meta")
+
+		ks.AppendKey(k)
+	}
+
+	return ks
+}
+
+func TestCopyTree(t *testing.T) {
+	ks := treeKeySet(t, "user:/tests/go/elektra/tree/src", "user:/tests/go/elektra/tree/src/a", "user:/tests/go/elektra/tree/src/a/b", "user:/tests/go/elektra/tree/srcb")
+	defer ks.Close()
+
+	src, _ := elektra.NewKey("user:/tests/go/elektra/tree/src")
+	dst, _ := elektra.NewKey("system:/tests/go/elektra/tree/dst")
+
+	err := elektra.CopyTree(ks, src, dst)
+	Check(t, err, "CopyTree failed")
+	Assertf(t, ks.Len() == 7, "KeySet should contain 7 keys but contains %d", ks.Len())
+
+	copied := ks.LookupByName("system:/tests/go/elektra/tree/dst/a/b")
+	Assert(t, copied != nil, "copied key not found")
+	Assertf(t, copied.String() == "value of user:/tests/go/elektra/tree/src/a/b", "copied key has wrong value %q", copied.String())
+	Assertf(t, copied.Meta("meta") == "user:/tests/go/elektra/tree/src/a/b", "copied key has wrong meta %q", copied.Meta("meta"))
+
+	Assert(t, ks.LookupByName("system:/tests/go/elektra/tree/dstb") == nil, "sibling srcb should not be copied")
+}
+
+func TestMoveTree(t *testing.T) {
+	ks := treeKeySet(t, "user:/tests/go/elektra/tree/src", "user:/tests/go/elektra/tree/src/a", "dir:/tests/go/elektra/tree/src/c")
+	defer ks.Close()
+
+	src, _ := elektra.NewKey("/tests/go/elektra/tree/src")
+	dst, _ := elektra.NewKey("/tests/go/elektra/tree/dst")
+
+	err := elektra.MoveTree(ks, src, dst)
+	Check(t, err, "MoveTree failed")
+	Assertf(t, ks.Len() == 3, "KeySet should contain 3 keys but contains %d", ks.Len())
+
+	for _, name := range []string{"user:/tests/go/elektra/tree/dst", "user:/tests/go/elektra/tree/dst/a", "dir:/tests/go/elektra/tree/dst/c"} {
+		Assertf(t, ks.LookupByName(name) != nil, "moved key %q not found", name)
+	}
+}
+
+func TestMoveTreeKeepsSource(t *testing.T) {
+	ks := treeKeySet(t, "user:/tests/go/elektra/tree/src", "user:/tests/go/elektra/tree/src/a")
+	defer ks.Close()
+
+	src := ks.LookupByName("user:/tests/go/elektra/tree/src")
+	dst, _ := elektra.NewKey("user:/tests/go/elektra/tree/dst")
+
+	err := elektra.MoveTree(ks, src, dst)
+	Check(t, err, "MoveTree failed")
+	Assertf(t, src.Name() == "user:/tests/go/elektra/tree/src", "src should still be usable but is called %q", src.Name())
+	src.Close()
+}
+
+func TestMoveTreeCascadingSource(t *testing.T) {
+	ks := treeKeySet(t, "user:/tests/go/elektra/tree/src", "system:/tests/go/elektra/tree/src")
+	defer ks.Close()
+
+	src, _ := elektra.NewKey("/tests/go/elektra/tree/src")
+	dst, _ := elektra.NewKey("user:/tests/go/elektra/tree/dst")
+
+	err := elektra.MoveTree(ks, src, dst)
+	Assert(t, errors.Is(err, elektra.ErrCascadingSource), "a cascading src with a namespaced dst should return ErrCascadingSource")
+	Assertf(t, ks.Len() == 2, "KeySet should be unchanged but contains %d keys", ks.Len())
+}
+
+func TestMoveTreeBelowSource(t *testing.T) {
+	ks := treeKeySet(t, "user:/tests/go/elektra/tree/src", "user:/tests/go/elektra/tree/src/dst", "user:/tests/go/elektra/tree/src/dst/a")
+	defer ks.Close()
+
+	src, _ := elektra.NewKey("user:/tests/go/elektra/tree/src")
+	dst, _ := elektra.NewKey("user:/tests/go/elektra/tree/src/dst")
+
+	err := elektra.MoveTree(ks, src, dst)
+	Check(t, err, "MoveTree failed")
+	Assertf(t, ks.Len() == 3, "KeySet should contain 3 keys but contains %d", ks.Len())
+
+	for name, value := range map[string]string{
+		"user:/tests/go/elektra/tree/src/dst":       "user:/tests/go/elektra/tree/src",
+		"user:/tests/go/elektra/tree/src/dst/dst":   "user:/tests/go/elektra/tree/src/dst",
+		"user:/tests/go/elektra/tree/src/dst/dst/a": "user:/tests/go/elektra/tree/src/dst/a",
+	} {
+		k := ks.LookupByName(name)
+		Assertf(t, k != nil, "moved key %q not found", name)
+		Assertf(t, k.String() == "value of "+value, "moved key %q has wrong value %q", name, k.String())
+	}
+}
+
+func TestRemoveTree(t *testing.T) {
+	ks := treeKeySet(t, "user:/tests/go/elektra/tree/src", "user:/tests/go/elektra/tree/src/a", "user:/tests/go/elektra/tree/other")
+	defer ks.Close()
+
+	src, _ := elektra.NewKey("user:/tests/go/elektra/tree/src")
+
+	err := elektra.RemoveTree(ks, src)
+	Check(t, err, "RemoveTree failed")
+	Assertf(t, ks.Len() == 1, "KeySet should contain 1 key but contains %d", ks.Len())
+
+	err = elektra.RemoveTree(ks, src)
+	Assert(t, errors.Is(err, elektra.ErrKeyNotFound), "removing a missing tree should return ErrKeyNotFound")
+}
+
+func TestMoveTreeKDB(t *testing.T) {
+	kdb := elektra.New()
+	err := kdb.Open()
+	Check(t, err, "could not open KDB")
+	defer kdb.Close()
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/elektra/movetree")
+	src, _ := elektra.NewKey("user:/tests/go/elektra/movetree/src")
+	dst, _ := elektra.NewKey("user:/tests/go/elektra/movetree/dst")
+
+	ks := elektra.NewKeySet()
+	_, err = kdb.Get(ks, parentKey)
+	Check(t, err, "could not Get KeySet")
+
+	ks.Append(treeKeySet(t, "user:/tests/go/elektra/movetree/src", "user:/tests/go/elektra/movetree/src/a"))
+	_, err = kdb.Set(ks, parentKey)
+	Check(t, err, "could not Set KeySet")
+
+	err = elektra.MoveTreeKDB(kdb, src, dst)
+	Check(t, err, "MoveTreeKDB failed")
+
+	result := elektra.NewKeySet()
+	_, err = kdb.Get(result, parentKey)
+	Check(t, err, "could not Get KeySet")
+
+	Assert(t, result.LookupByName("user:/tests/go/elektra/movetree/dst/a") != nil, "moved key not found")
+	Assert(t, result.LookupByName("user:/tests/go/elektra/movetree/src/a") == nil, "source key was not removed")
+
+	err = elektra.RemoveTreeKDB(kdb, dst)
+	Check(t, err, "RemoveTreeKDB failed")
+}
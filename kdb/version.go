@@ -0,0 +1,151 @@
+package kdb
+
+// #include <kdb.h>
+//
+// #if KDB_VERSION_MAJOR == 0 && KDB_VERSION_MINOR < 9
+// #error "go-elektra does not support libelektra releases before 0.9, see README.md"
+// #endif
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrLibraryTooOld is returned by CheckVersion if the installed libelektra
+// is older than the headers this package was compiled against.
+var ErrLibraryTooOld = errors.New("libelektra is too old")
+
+// VersionInfo is a libelektra version.
+type VersionInfo struct {
+	Major, Minor, Micro int
+}
+
+func (v VersionInfo) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Micro)
+}
+
+// Less reports whether `v` is older than `other`.
+func (v VersionInfo) Less(other VersionInfo) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+
+	return v.Micro < other.Micro
+}
+
+// ParseVersion parses a version in the format Major.Minor.Micro.
+func ParseVersion(version string) (VersionInfo, error) {
+	parts := strings.Split(version, ".")
+
+	if len(parts) != 3 {
+		return VersionInfo{}, fmt.Errorf("invalid version %q", version)
+	}
+
+	var numbers [3]int
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+
+		if err != nil || n < 0 {
+			return VersionInfo{}, fmt.Errorf("invalid version %q", version)
+		}
+
+		numbers[i] = n
+	}
+
+	return VersionInfo{numbers[0], numbers[1], numbers[2]}, nil
+}
+
+// LibraryVersion returns the version of the libelektra headers
+// this package was compiled against.
+func LibraryVersion() VersionInfo {
+	return VersionInfo{
+		Major: int(C.KDB_VERSION_MAJOR),
+		Minor: int(C.KDB_VERSION_MINOR),
+		Micro: int(C.KDB_VERSION_MICRO),
+	}
+}
+
+// CheckVersion returns ErrLibraryTooOld if the libelektra used by `handle`
+// is older than the version this package was compiled against.
+func CheckVersion(handle KDB) error {
+	version, err := handle.Version()
+
+	if err != nil {
+		return err
+	}
+
+	installed, err := ParseVersion(version)
+
+	if err != nil {
+		return err
+	}
+
+	if compiled := LibraryVersion(); installed.Less(compiled) {
+		return fmt.Errorf("%w: libelektra %s is installed but go-elektra was compiled against %s", ErrLibraryTooOld, installed, compiled)
+	}
+
+	return nil
+}
+
+// FeatureReport describes which optional parts of libelektra are available.
+type FeatureReport struct {
+	// Version is the version this package was compiled against.
+	Version VersionInfo
+	// KsBelow is true if ksBelow and ksFindHierarchy are used, it is
+	// false if the package was built with the elektra_legacy tag.
+	KsBelow bool
+	// Notification is true if the notification library is installed. This is
+	// a heuristic: it only finds a shared libelektra-notification library in
+	// the directory of the libelektra this package is linked against, so it
+	// is false for static builds or if the libraries are installed elsewhere.
+	Notification bool
+	// HighLevel is true if the high-level API library is installed, it is
+	// detected with the same heuristic as Notification.
+	HighLevel bool
+	// Gopts is true if the gopts plugin can be loaded, see PluginInfo.
+	Gopts bool
+}
+
+// Features detects which optional parts of libelektra are available.
+func Features() (*FeatureReport, error) {
+	report := &FeatureReport{
+		Version: LibraryVersion(),
+		KsBelow: haveKsBelow,
+	}
+
+	if dir := libraryDir(); dir != "" {
+		report.Notification = libraryExists(dir, "libelektra-notification")
+		report.HighLevel = libraryExists(dir, "libelektra-highlevel")
+	}
+
+	_, err := PluginInfo("gopts")
+
+	switch {
+	case err == nil:
+		report.Gopts = true
+	case !errors.Is(err, ErrPluginNotFound):
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// libraryExists reports whether the shared library `name` is in `dir`.
+func libraryExists(dir, name string) bool {
+	for _, pattern := range []string{name + ".so*", name + ".dylib", name + ".*.dylib"} {
+		if matches, _ := filepath.Glob(filepath.Join(dir, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package kdb_test
+
+import (
+	"errors"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/kdb/kdbtest"
+	. "go.libelektra.org/test"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := elektra.ParseVersion("0.9.10")
+	Check(t, err, "ParseVersion failed")
+	Assertf(t, v == elektra.VersionInfo{Major: 0, Minor: 9, Micro: 10}, "wrong version %v", v)
+	Assert(t, v.String() == "0.9.10", "String should return the parsed version")
+	Assert(t, elektra.VersionInfo{Major: 0, Minor: 9, Micro: 9}.Less(v), "0.9.9 should be older than 0.9.10")
+
+	_, err = elektra.ParseVersion("0.9")
+	Assert(t, err != nil, "ParseVersion should fail for incomplete versions")
+}
+
+func TestCheckVersion(t *testing.T) {
+	Assert(t, !elektra.LibraryVersion().Less(elektra.VersionInfo{Minor: 9}), "LibraryVersion should be at least 0.9.0")
+
+	mock := kdbtest.NewMock(t)
+	mock.VersionString = elektra.LibraryVersion().String()
+	Check(t, elektra.CheckVersion(mock), "the compiled version should be accepted")
+
+	mock.VersionString = "0.8.26"
+	err := elektra.CheckVersion(mock)
+	Assert(t, errors.Is(err, elektra.ErrLibraryTooOld), "an older library should return ErrLibraryTooOld")
+}
+
+func TestFeatures(t *testing.T) {
+	features, err := elektra.Features()
+	Check(t, err, "Features failed")
+	Assert(t, features.Version == elektra.LibraryVersion(), "Features should report the compiled version")
+
+	_, err = elektra.PluginInfo("gopts")
+	gopts := err == nil
+
+	Assertf(t, features.Gopts == gopts, "Gopts should be %t if PluginInfo returns %v", gopts, err)
+}
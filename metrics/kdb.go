@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// KDB records the metrics of the operations of the wrapped KDB.
+type KDB struct {
+	next     elektra.KDB
+	registry *Registry
+
+	mu sync.Mutex
+	// conflicts holds the parent Keys whose last Set failed with a conflict
+	conflicts map[string]bool
+}
+
+var _ elektra.WarningsKDB = (*KDB)(nil)
+
+// Wrap returns a KDB that records the operations of `next` in `registry`.
+func Wrap(next elektra.KDB, registry *Registry) *KDB {
+	return &KDB{next: next, registry: registry, conflicts: map[string]bool{}}
+}
+
+// Middleware returns a Middleware for kdb.Chain that records into `registry`.
+func Middleware(registry *Registry) elektra.Middleware {
+	return func(next elektra.KDB) elektra.KDB {
+		return Wrap(next, registry)
+	}
+}
+
+// Open implements KDB.
+func (k *KDB) Open() error {
+	start := time.Now()
+	err := k.next.Open()
+	k.registry.observe("open", time.Since(start), -1, errorNumber(err))
+
+	return err
+}
+
+// Close implements KDB.
+func (k *KDB) Close() error {
+	start := time.Now()
+	err := k.next.Close()
+	k.registry.observe("close", time.Since(start), -1, errorNumber(err))
+
+	return err
+}
+
+// Get implements KDB.
+func (k *KDB) Get(keySet elektra.KeySet, parentKey elektra.Key) (bool, error) {
+	changed, _, err := k.GetWithWarnings(keySet, parentKey)
+
+	return changed, err
+}
+
+// GetWithWarnings implements WarningsKDB, the warnings
+// of the wrapped KDB are passed on.
+func (k *KDB) GetWithWarnings(keySet elektra.KeySet, parentKey elektra.Key) (bool, []elektra.ElektraWarning, error) {
+	start := time.Now()
+	changed, warnings, err := elektra.GetWithWarnings(k.next, keySet, parentKey)
+	k.registry.observe("get", time.Since(start), size(keySet), errorNumber(err))
+
+	return changed, warnings, err
+}
+
+// Set implements KDB, a Set after a conflicting Set with the
+// same parent Key is counted as conflict retry.
+func (k *KDB) Set(keySet elektra.KeySet, parentKey elektra.Key) (bool, error) {
+	changed, _, err := k.SetWithWarnings(keySet, parentKey)
+
+	return changed, err
+}
+
+// SetWithWarnings implements WarningsKDB, see Set.
+func (k *KDB) SetWithWarnings(keySet elektra.KeySet, parentKey elektra.Key) (bool, []elektra.ElektraWarning, error) {
+	start := time.Now()
+	changed, warnings, err := elektra.SetWithWarnings(k.next, keySet, parentKey)
+	k.registry.observe("set", time.Since(start), size(keySet), errorNumber(err))
+
+	if parentKey == nil {
+		return changed, warnings, err
+	}
+
+	name := parentKey.Name()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.conflicts[name] {
+		k.registry.conflictRetry()
+	}
+
+	if errors.Is(err, elektra.ErrConflictingState) {
+		k.conflicts[name] = true
+	} else {
+		delete(k.conflicts, name)
+	}
+
+	return changed, warnings, err
+}
+
+// Version implements KDB, it is not recorded.
+func (k *KDB) Version() (string, error) {
+	return k.next.Version()
+}
+
+// size returns the length of `keySet` or -1 if it is nil.
+func size(keySet elektra.KeySet) int {
+	if keySet == nil {
+		return -1
+	}
+
+	return keySet.Len()
+}
+
+// errorNumber returns the number of an ElektraError, "unknown"
+// for other errors and an empty string if `err` is nil.
+func errorNumber(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var elektraErr *elektra.ElektraError
+
+	if errors.As(err, &elektraErr) {
+		return elektraErr.Number
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,77 @@
+package metrics_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/kdb/kdbtest"
+	"go.libelektra.org/metrics"
+	. "go.libelektra.org/test"
+)
+
+func TestMetrics(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	mock.Fail("Set", 1, kdbtest.Error(elektra.ErrConflictingState, "conflict"))
+
+	registry := metrics.NewRegistry()
+	kdb := metrics.Wrap(mock, registry)
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/metrics")
+	ks := kdbtest.KeySetFromMap(map[string]string{"user:/tests/go/metrics/key": "value"})
+
+	_, err := kdb.Set(ks, parentKey)
+	Assert(t, err != nil, "first Set should fail")
+
+	_, err = kdb.Set(ks, parentKey)
+	Check(t, err, "second Set failed")
+
+	_, err = kdb.Get(elektra.NewKeySet(), parentKey)
+	Check(t, err, "Get failed")
+
+	var buf bytes.Buffer
+	n, err := registry.WriteTo(&buf)
+	Check(t, err, "WriteTo failed")
+	Assert(t, n == int64(buf.Len()), "WriteTo returned the wrong length")
+
+	output := buf.String()
+
+	for _, line := range []string{
+		`elektra_kdb_operations_total{operation="set"} 2`,
+		`elektra_kdb_operations_total{operation="get"} 1`,
+		`elektra_kdb_errors_total{operation="set",number="C02000"} 1`,
+		`elektra_kdb_conflict_retries_total 1`,
+		`elektra_kdb_keyset_size_bucket{operation="get",le="1"} 1`,
+		`elektra_kdb_operation_duration_seconds_count{operation="set"} 2`,
+		`# TYPE elektra_kdb_operation_duration_seconds histogram`,
+	} {
+		Assertf(t, strings.Contains(output, line+"\n"), "output is missing %q:\n%s", line, output)
+	}
+}
+
+func TestMetricsNilKeySet(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	mock.Fail("Get", 0, kdbtest.Error(elektra.ErrResource, "backend unavailable"))
+
+	kdb := metrics.Wrap(mock, metrics.NewRegistry())
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/metrics")
+
+	_, err := kdb.Get(nil, parentKey)
+	Assert(t, errors.Is(err, elektra.ErrResource), "the error of the wrapped KDB should be returned")
+}
+
+func TestMetricsWarnings(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	mock.Warn("Get", elektra.ElektraWarning{ElektraError: *kdbtest.Error(elektra.ErrPluginMisbehavior, "deprecated key")})
+
+	kdb := metrics.Wrap(mock, metrics.NewRegistry())
+
+	parentKey, _ := elektra.NewKey("user:/tests/go/metrics")
+
+	_, warnings, err := elektra.GetWithWarnings(kdb, elektra.NewKeySet(), parentKey)
+	Check(t, err, "Get failed")
+	Assertf(t, len(warnings) == 1, "the warnings of the wrapped KDB should be passed on: %v", warnings)
+}
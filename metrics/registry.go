@@ -0,0 +1,204 @@
+// Package metrics records metrics of KDB operations and writes them
+// in the Prometheus text format, without depending on a Prometheus client.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// DurationBuckets are the upper bounds in seconds of the duration histogram.
+	DurationBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// SizeBuckets are the upper bounds of the KeySet size histogram.
+	SizeBuckets = []float64{1, 10, 100, 1000, 10000, 100000}
+)
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+
+	h.sum += v
+	h.count++
+}
+
+type errorLabels struct {
+	operation, number string
+}
+
+// Registry holds the metrics of one or more KDBs, it is safe for concurrent use.
+type Registry struct {
+	mu              sync.Mutex
+	operations      map[string]uint64
+	durations       map[string]*histogram
+	sizes           map[string]*histogram
+	errors          map[errorLabels]uint64
+	conflictRetries uint64
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		operations: map[string]uint64{},
+		durations:  map[string]*histogram{},
+		sizes:      map[string]*histogram{},
+		errors:     map[errorLabels]uint64{},
+	}
+}
+
+// observe records an operation, `size` is negative if there is no KeySet
+// and `number` is the Elektra error number or empty.
+func (r *Registry) observe(operation string, duration time.Duration, size int, number string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.operations[operation]++
+
+	if r.durations[operation] == nil {
+		r.durations[operation] = newHistogram(DurationBuckets)
+	}
+
+	r.durations[operation].observe(duration.Seconds())
+
+	if size >= 0 {
+		if r.sizes[operation] == nil {
+			r.sizes[operation] = newHistogram(SizeBuckets)
+		}
+
+		r.sizes[operation].observe(float64(size))
+	}
+
+	if number != "" {
+		r.errors[errorLabels{operation, number}]++
+	}
+}
+
+func (r *Registry) conflictRetry() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.conflictRetries++
+}
+
+// WriteTo writes all metrics in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	header(bw, "elektra_kdb_operations_total", "counter", "Number of KDB operations.")
+
+	for _, op := range sortedKeys(r.operations) {
+		fmt.Fprintf(bw, "elektra_kdb_operations_total{operation=%s} %d\n", quote(op), r.operations[op])
+	}
+
+	header(bw, "elektra_kdb_operation_duration_seconds", "histogram", "Duration of KDB operations.")
+
+	for _, op := range sortedKeys(r.durations) {
+		writeHistogram(bw, "elektra_kdb_operation_duration_seconds", op, r.durations[op])
+	}
+
+	header(bw, "elektra_kdb_keyset_size", "histogram", "Number of Keys in the KeySet after Get and Set.")
+
+	for _, op := range sortedKeys(r.sizes) {
+		writeHistogram(bw, "elektra_kdb_keyset_size", op, r.sizes[op])
+	}
+
+	header(bw, "elektra_kdb_errors_total", "counter", "Number of failed KDB operations by Elektra error number.")
+
+	labels := make([]errorLabels, 0, len(r.errors))
+
+	for l := range r.errors {
+		labels = append(labels, l)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].operation != labels[j].operation {
+			return labels[i].operation < labels[j].operation
+		}
+
+		return labels[i].number < labels[j].number
+	})
+
+	for _, l := range labels {
+		fmt.Fprintf(bw, "elektra_kdb_errors_total{operation=%s,number=%s} %d\n", quote(l.operation), quote(l.number), r.errors[l])
+	}
+
+	header(bw, "elektra_kdb_conflict_retries_total", "counter", "Number of Sets after a conflict with the same parent Key.")
+	fmt.Fprintf(bw, "elektra_kdb_conflict_retries_total %d\n", r.conflictRetries)
+
+	err := bw.Flush()
+
+	return cw.n, err
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeHistogram(w io.Writer, name, op string, h *histogram) {
+	for i, bound := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{operation=%s,le=\"%s\"} %d\n", name, quote(op), formatFloat(bound), h.counts[i])
+	}
+
+	fmt.Fprintf(w, "%s_bucket{operation=%s,le=\"+Inf\"} %d\n", name, quote(op), h.count)
+	fmt.Fprintf(w, "%s_sum{operation=%s} %s\n", name, quote(op), formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count{operation=%s} %d\n", name, quote(op), h.count)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote quotes a label value.
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
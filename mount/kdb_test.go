@@ -0,0 +1,59 @@
+package mount_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go.libelektra.org/kdb/kdbtest"
+	"go.libelektra.org/mount"
+	. "go.libelektra.org/test"
+)
+
+func listed(t *testing.T, mountpoints []mount.Mountpoint, name string) bool {
+	t.Helper()
+
+	for _, mp := range mountpoints {
+		if mp.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestMountKDB(t *testing.T) {
+	kdbtest.Isolate(t)
+	handle := kdbtest.Open(t)
+
+	mp := mount.Mountpoint{
+		Name:     "user:/tests/go/elektra/mount/kdb",
+		Path:     filepath.Join(t.TempDir(), "mount.ecf"),
+		Resolver: mount.Plugin{Name: "resolver"},
+		Storage:  mount.Plugin{Name: "dump"},
+	}
+
+	err := mount.Mount(handle, mp)
+	Checkf(t, err, "Mount failed: %v", err)
+
+	t.Cleanup(func() {
+		_ = mount.Umount(handle, mp.Name)
+	})
+
+	mountpoints, err := mount.List(handle)
+	Checkf(t, err, "List failed: %v", err)
+	Assertf(t, listed(t, mountpoints, mp.Name), "%q should be listed: %v", mp.Name, mountpoints)
+
+	err = mount.Mount(handle, mp)
+	Assert(t, errors.Is(err, mount.ErrMountpointExists), "mounting twice should return ErrMountpointExists")
+
+	err = mount.Umount(handle, mp.Name)
+	Checkf(t, err, "Umount failed: %v", err)
+
+	mountpoints, err = mount.List(handle)
+	Checkf(t, err, "List failed: %v", err)
+	Assertf(t, !listed(t, mountpoints, mp.Name), "%q should not be listed after Umount", mp.Name)
+
+	err = mount.Umount(handle, mp.Name)
+	Assert(t, errors.Is(err, mount.ErrMountpointNotFound), "unmounting twice should return ErrMountpointNotFound")
+}
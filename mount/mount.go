@@ -0,0 +1,522 @@
+// Package mount manages the mountpoints stored below
+// system:/elektra/mountpoints, like `kdb mount` and `kdb umount` do.
+package mount
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+const (
+	mountpointsKey = "system:/elektra/mountpoints"
+
+	backendRootValue = "This is a configuration for a backend, see subkeys for more information"
+)
+
+var (
+	ErrMountpointExists   = errors.New("mountpoint already exists")
+	ErrMountpointNotFound = errors.New("mountpoint not found")
+	ErrPluginNotFound     = elektra.ErrPluginNotFound
+	ErrPlacement          = errors.New("plugin can not be placed")
+)
+
+// Plugin is a plugin of a mountpoint together with its configuration.
+type Plugin struct {
+	Name   string
+	Config map[string]string
+}
+
+// Mountpoint describes a backend mounted into the key database.
+type Mountpoint struct {
+	// Name is the mountpoint, e.g. "user:/tests" or "/tests".
+	Name string
+	// Path is the configuration file of the backend.
+	Path string
+	// Config is the backend configuration shared by all plugins,
+	// `path` excluded.
+	Config map[string]string
+
+	Resolver Plugin
+	Storage  Plugin
+	Plugins  []Plugin
+}
+
+// roles in the order libelektra processes them when opening a backend,
+// the first reference of a plugin has to define it.
+var roles = []string{"errorplugins", "getplugins", "setplugins"}
+
+type position struct {
+	role        string
+	first, last int
+}
+
+// placementPositions maps the placements of plugin contracts to
+// the positions in the backend configuration.
+var placementPositions = map[string]position{
+	"prerollback":    {"errorplugins", 0, 4},
+	"rollback":       {"errorplugins", 5, 5},
+	"postrollback":   {"errorplugins", 6, 9},
+	"getresolver":    {"getplugins", 0, 0},
+	"pregetstorage":  {"getplugins", 1, 4},
+	"getstorage":     {"getplugins", 5, 5},
+	"postgetstorage": {"getplugins", 6, 9},
+	"setresolver":    {"setplugins", 0, 0},
+	"presetstorage":  {"setplugins", 1, 4},
+	"setstorage":     {"setplugins", 5, 5},
+	"precommit":      {"setplugins", 6, 6},
+	"commit":         {"setplugins", 7, 7},
+	"postcommit":     {"setplugins", 8, 9},
+}
+
+var (
+	resolverPlacements = []string{"rollback", "getresolver", "setresolver", "commit"}
+	storagePlacements  = []string{"getstorage", "setstorage"}
+)
+
+// List returns all mountpoints found below system:/elektra/mountpoints.
+func List(handle elektra.KDB) ([]Mountpoint, error) {
+	ks, err := getMountpoints(handle)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer ks.Close()
+
+	return fromKeySet(ks)
+}
+
+// Mount adds the mountpoint `mp` after validating that all of its plugins
+// are installed and that it does not collide with an existing mountpoint.
+func Mount(handle elektra.KDB, mp Mountpoint) error {
+	name, err := canonicalName(mp.Name)
+
+	if err != nil {
+		return err
+	}
+
+	mp.Name = name
+
+	placements := map[string][]string{}
+
+	for _, plugin := range append([]Plugin{mp.Resolver, mp.Storage}, mp.Plugins...) {
+		contract, err := pluginPlacements(plugin.Name)
+
+		if err != nil {
+			return err
+		}
+
+		placements[plugin.Name] = contract
+	}
+
+	parentKey, err := elektra.NewKey(mountpointsKey)
+
+	if err != nil {
+		return err
+	}
+
+	defer parentKey.Close()
+
+	ks, err := getMountpoints(handle)
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+
+	existing, err := fromKeySet(ks)
+
+	if err != nil {
+		return err
+	}
+
+	for _, other := range existing {
+		if collides(mp.Name, other.Name) {
+			return fmt.Errorf("%w: %q collides with %q", ErrMountpointExists, mp.Name, other.Name)
+		}
+	}
+
+	mpKeySet, err := toKeySet(mp, placements)
+
+	if err != nil {
+		return err
+	}
+
+	defer mpKeySet.Close()
+
+	ks.Append(mpKeySet)
+
+	_, err = handle.Set(ks, parentKey)
+
+	return err
+}
+
+// Umount removes the mountpoint `name`.
+func Umount(handle elektra.KDB, name string) error {
+	name, err := canonicalName(name)
+
+	if err != nil {
+		return err
+	}
+
+	parentKey, err := elektra.NewKey(mountpointsKey)
+
+	if err != nil {
+		return err
+	}
+
+	defer parentKey.Close()
+
+	ks, err := getMountpoints(handle)
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+
+	rootKey, err := elektra.NewKey(mountpointsKey + "/" + elektra.EscapeBaseName(name))
+
+	if err != nil {
+		return err
+	}
+
+	defer rootKey.Close()
+
+	removed := ks.Cut(rootKey)
+
+	if removed == nil || removed.Len() == 0 {
+		return fmt.Errorf("%w: %q", ErrMountpointNotFound, name)
+	}
+
+	defer removed.Close()
+
+	_, err = handle.Set(ks, parentKey)
+
+	return err
+}
+
+func getMountpoints(handle elektra.KDB) (elektra.KeySet, error) {
+	parentKey, err := elektra.NewKey(mountpointsKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer parentKey.Close()
+
+	ks := elektra.NewKeySet()
+
+	if _, err = handle.Get(ks, parentKey); err != nil {
+		ks.Close()
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// pluginPlacements loads the contract of the plugin `name` and returns
+// the placements it declares. It fails if the plugin is not installed.
+func pluginPlacements(name string) ([]string, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: empty plugin name", ErrPluginNotFound)
+	}
+
+	contract, err := elektra.PluginInfo(name)
+
+	if errors.Is(err, elektra.ErrPluginNotFound) {
+		return nil, fmt.Errorf("%w: %q", ErrPluginNotFound, name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return contract.Placements, nil
+}
+
+// toKeySet serializes the mountpoint into the Keys `kdb mount` writes.
+// `placements` contains the contract placements of the extra plugins.
+func toKeySet(mp Mountpoint, placements map[string][]string) (elektra.KeySet, error) {
+	type entry struct {
+		plugin Plugin
+		label  string
+	}
+
+	slots := map[string][10]*entry{}
+
+	place := func(e *entry, names []string) error {
+		for _, name := range names {
+			pos, ok := placementPositions[name]
+
+			if !ok {
+				return fmt.Errorf("%w: unknown placement %q of %q", ErrPlacement, name, e.plugin.Name)
+			}
+
+			s := slots[pos.role]
+			i := pos.first
+
+			for ; i <= pos.last && s[i] != nil; i++ {
+			}
+
+			if i > pos.last {
+				return fmt.Errorf("%w: no free slot in %q for %q", ErrPlacement, name, e.plugin.Name)
+			}
+
+			s[i] = e
+			slots[pos.role] = s
+		}
+
+		return nil
+	}
+
+	if err := place(&entry{mp.Resolver, "resolver"}, resolverPlacements); err != nil {
+		return nil, err
+	}
+
+	if err := place(&entry{mp.Storage, mp.Storage.Name}, storagePlacements); err != nil {
+		return nil, err
+	}
+
+	for _, plugin := range mp.Plugins {
+		if err := place(&entry{plugin, plugin.Name}, placements[plugin.Name]); err != nil {
+			return nil, err
+		}
+	}
+
+	root := mountpointsKey + "/" + elektra.EscapeBaseName(mp.Name)
+	ks := elektra.NewKeySet()
+
+	add := func(name, value string) error {
+		k, err := elektra.NewKey(name, value)
+
+		if err != nil {
+			return fmt.Errorf("invalid key %q: %w", name, err)
+		}
+
+		ks.AppendKey(k)
+
+		return nil
+	}
+
+	values := [][2]string{
+		{root, backendRootValue},
+		{root + "/mountpoint", mp.Name},
+		{root + "/config", ""},
+		{root + "/config/path", mp.Path},
+	}
+
+	for _, name := range sortedKeys(mp.Config) {
+		values = append(values, [2]string{root + "/config/" + name, mp.Config[name]})
+	}
+
+	defined := map[*entry]bool{}
+
+	for _, role := range roles {
+		values = append(values, [2]string{root + "/" + role, ""})
+
+		for i, e := range slots[role] {
+			if e == nil {
+				continue
+			}
+
+			if defined[e] {
+				values = append(values, [2]string{fmt.Sprintf("%s/%s/#%d#%s", root, role, i, e.label), ""})
+				continue
+			}
+
+			defined[e] = true
+			entryName := fmt.Sprintf("%s/%s/#%d#%s#%s#", root, role, i, e.plugin.Name, e.label)
+			values = append(values, [2]string{entryName, ""})
+
+			for _, name := range sortedKeys(e.plugin.Config) {
+				values = append(values, [2]string{entryName + "/config/" + name, e.plugin.Config[name]})
+			}
+		}
+	}
+
+	for _, v := range values {
+		if err := add(v[0], v[1]); err != nil {
+			ks.Close()
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+// fromKeySet parses all mountpoints below system:/elektra/mountpoints.
+func fromKeySet(ks elektra.KeySet) ([]Mountpoint, error) {
+	parent, err := elektra.NewKey(mountpointsKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer parent.Close()
+
+	var mountpoints []Mountpoint
+
+	for _, root := range ks.ToSlice() {
+		if !root.IsDirectlyBelow(parent) {
+			continue
+		}
+
+		mp, err := parseMountpoint(ks, root)
+
+		if err != nil {
+			return nil, err
+		}
+
+		mountpoints = append(mountpoints, mp)
+	}
+
+	return mountpoints, nil
+}
+
+func parseMountpoint(ks elektra.KeySet, root elektra.Key) (Mountpoint, error) {
+	rootName := root.Name()
+
+	mp := Mountpoint{
+		Name:   root.BaseName(),
+		Config: map[string]string{},
+	}
+
+	if k := ks.LookupByName(rootName + "/mountpoint"); k != nil {
+		mp.Name = k.String()
+	}
+
+	plugins := map[string]*Plugin{}
+	var order []string
+	resolverLabel, storageLabel := "", ""
+
+	for _, k := range ks.ToSlice() {
+		if !k.IsBelow(root) {
+			continue
+		}
+
+		rel := strings.TrimPrefix(k.Name(), rootName+"/")
+		parts := strings.SplitN(rel, "/", 3)
+
+		switch {
+		case parts[0] == "config" && len(parts) > 1:
+			name := strings.TrimPrefix(rel, "config/")
+
+			if name == "path" {
+				mp.Path = k.String()
+			} else {
+				mp.Config[name] = k.String()
+			}
+		case len(parts) > 1 && (parts[0] == "errorplugins" || parts[0] == "getplugins" || parts[0] == "setplugins"):
+			pos, name, label, err := parseReference(parts[1])
+
+			if err != nil {
+				return mp, fmt.Errorf("mountpoint %q: %w", mp.Name, err)
+			}
+
+			if name != "" && plugins[label] == nil {
+				plugins[label] = &Plugin{Name: name, Config: map[string]string{}}
+				order = append(order, label)
+			}
+
+			if len(parts) == 3 && strings.HasPrefix(parts[2], "config/") && plugins[label] != nil {
+				plugins[label].Config[strings.TrimPrefix(parts[2], "config/")] = k.String()
+			}
+
+			if parts[0] == "getplugins" && pos == 0 {
+				resolverLabel = label
+			} else if parts[0] == "getplugins" && pos == 5 {
+				storageLabel = label
+			}
+		}
+	}
+
+	for _, label := range order {
+		switch label {
+		case resolverLabel:
+			mp.Resolver = *plugins[label]
+		case storageLabel:
+			mp.Storage = *plugins[label]
+		default:
+			mp.Plugins = append(mp.Plugins, *plugins[label])
+		}
+	}
+
+	return mp, nil
+}
+
+// parseReference parses plugin references like `#5#dump#dump#`,
+// which defines a plugin, or `#5#dump`, which refers to a defined one.
+func parseReference(ref string) (pos int, name, label string, err error) {
+	parts := strings.Split(ref, "#")
+
+	if len(parts) < 3 || parts[0] != "" {
+		return 0, "", "", fmt.Errorf("invalid plugin reference %q", ref)
+	}
+
+	pos, err = strconv.Atoi(parts[1])
+
+	if err != nil {
+		return 0, "", "", fmt.Errorf("invalid plugin position in %q", ref)
+	}
+
+	if len(parts) == 5 && parts[4] == "" {
+		return pos, parts[2], parts[3], nil
+	}
+
+	return pos, "", parts[2], nil
+}
+
+// canonicalName returns the name of the mountpoint as Elektra would print it.
+func canonicalName(name string) (string, error) {
+	k, err := elektra.NewKey(name)
+
+	if err != nil {
+		return "", fmt.Errorf("invalid mountpoint %q: %w", name, err)
+	}
+
+	defer k.Close()
+
+	return k.Name(), nil
+}
+
+// collides checks if two mountpoints would mount the same keys,
+// cascading mountpoints mount into every namespace.
+func collides(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	nsA, pathA := splitNamespace(a)
+	nsB, pathB := splitNamespace(b)
+
+	return (nsA == "" || nsB == "") && pathA == pathB
+}
+
+func splitNamespace(name string) (string, string) {
+	index := strings.Index(name, "/")
+
+	if index < 0 {
+		return name, "/"
+	}
+
+	return name[:index], name[index:]
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,112 @@
+package mount
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	. "go.libelektra.org/test"
+)
+
+func TestToKeySet(t *testing.T) {
+	mp := Mountpoint{
+		Name:     "user:/tests/go/elektra/mount",
+		Path:     "mount.ecf",
+		Config:   map[string]string{},
+		Resolver: Plugin{Name: "resolver_fm_hpu_b", Config: map[string]string{}},
+		Storage:  Plugin{Name: "dump", Config: map[string]string{"format": "binary"}},
+		Plugins: []Plugin{
+			{Name: "type", Config: map[string]string{}},
+		},
+	}
+
+	placements := map[string][]string{
+		"type": {"presetstorage", "postgetstorage"},
+	}
+
+	ks, err := toKeySet(mp, placements)
+	Check(t, err, "could not serialize mountpoint")
+	defer ks.Close()
+
+	root := `system:/elektra/mountpoints/user:\/tests\/go\/elektra\/mount`
+
+	for _, name := range []string{
+		root + "/mountpoint",
+		root + "/config/path",
+		root + "/errorplugins/#5#resolver_fm_hpu_b#resolver#",
+		root + "/getplugins/#0#resolver",
+		root + "/getplugins/#5#dump#dump#",
+		root + "/getplugins/#5#dump#dump#/config/format",
+		root + "/getplugins/#6#type#type#",
+		root + "/setplugins/#0#resolver",
+		root + "/setplugins/#1#type",
+		root + "/setplugins/#5#dump",
+		root + "/setplugins/#7#resolver",
+	} {
+		Assertf(t, ks.LookupByName(name) != nil, "KeySet does not contain %q", name)
+	}
+
+	mountpoints, err := fromKeySet(ks)
+	Check(t, err, "could not parse mountpoints")
+	Assertf(t, len(mountpoints) == 1, "expected 1 mountpoint but got %d", len(mountpoints))
+	Assertf(t, reflect.DeepEqual(mountpoints[0], mp), "parsed mountpoint %+v differs from %+v", mountpoints[0], mp)
+}
+
+func TestToKeySetInvalidPlacement(t *testing.T) {
+	mp := Mountpoint{
+		Name:     "user:/tests/go/elektra/mount",
+		Resolver: Plugin{Name: "resolver"},
+		Storage:  Plugin{Name: "dump"},
+		Plugins:  []Plugin{{Name: "other"}},
+	}
+
+	_, err := toKeySet(mp, map[string][]string{"other": {"getstorage"}})
+	Assert(t, err != nil, "placing two storage plugins should fail")
+}
+
+var referenceTests = []struct {
+	ref   string
+	pos   int
+	name  string
+	label string
+}{
+	{"#0#resolver_fm_hpu_b#resolver#", 0, "resolver_fm_hpu_b", "resolver"},
+	{"#5#dump", 5, "", "dump"},
+}
+
+func TestParseReference(t *testing.T) {
+	for _, test := range referenceTests {
+		pos, name, label, err := parseReference(test.ref)
+		Check(t, err, fmt.Sprintf("could not parse %q", test.ref))
+		Assertf(t, pos == test.pos && name == test.name && label == test.label,
+			"parseReference(%q) = %d, %q, %q", test.ref, pos, name, label)
+	}
+
+	_, _, _, err := parseReference("resolver")
+	Assert(t, err != nil, "parsing an invalid reference should fail")
+}
+
+var collidesTests = []struct {
+	a, b     string
+	expected bool
+}{
+	{"user:/tests", "user:/tests", true},
+	{"/tests", "user:/tests", true},
+	{"system:/tests", "user:/tests", false},
+	{"user:/tests", "user:/tests/sub", false},
+}
+
+func TestCollides(t *testing.T) {
+	for _, test := range collidesTests {
+		Assertf(t, collides(test.a, test.b) == test.expected, "collides(%q, %q) should be %t", test.a, test.b, test.expected)
+	}
+}
+
+func TestCanonicalName(t *testing.T) {
+	name, err := canonicalName("user:/tests//go/")
+	Check(t, err, "could not canonicalize name")
+
+	k, _ := elektra.NewKey("user:/tests/go")
+	Assertf(t, name == k.Name(), "canonical name should be %q but is %q", k.Name(), name)
+}
@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// metaFormat is stored in the `error` and `warnings/#N` meta Keys
+// and describes the meta Keys below them.
+const metaFormat = "number description reason module file line mountpoint configfile"
+
+// codes contains the error codes in the order they are checked
+// when converting a Go error, more specific codes come first.
+var codes = []error{
+	elektra.ErrOutOfMemory,
+	elektra.ErrResource,
+	elektra.ErrInstallation,
+	elektra.ErrInternal,
+	elektra.ErrInterface,
+	elektra.ErrPluginMisbehavior,
+	elektra.ErrLogical,
+	elektra.ErrPermanent,
+	elektra.ErrConflictingState,
+	elektra.ErrValidationSyntactic,
+	elektra.ErrValidationSemantic,
+	elektra.ErrValidation,
+}
+
+// SetError sets the error `code`, e.g. `kdb.ErrValidationSemantic`, as
+// `error/*` meta Keys on the parent Key, like ELEKTRA_SET_ERRORF does.
+// It only returns an error if the meta Keys could not be set, the plugin
+// still has to return StatusError.
+func SetError(parentKey elektra.Key, code error, format string, args ...interface{}) error {
+	e := elektra.NewElektraError(code, fmt.Sprintf(format, args...))
+	_, file, line, _ := runtime.Caller(1)
+
+	return setProblem(parentKey, "error", e.Number, e.Description, e.Reason, file, line)
+}
+
+// AddWarning adds the warning `code` as `warnings/#N/*` meta Keys
+// on the parent Key, like ELEKTRA_ADD_WARNINGF does.
+func AddWarning(parentKey elektra.Key, code error, format string, args ...interface{}) error {
+	e := elektra.NewElektraError(code, fmt.Sprintf(format, args...))
+	_, file, line, _ := runtime.Caller(1)
+
+	index := elektra.ArrayIndex(0)
+
+	if last := parentKey.Meta("warnings"); last != "" {
+		n, err := elektra.ParseArrayIndex(last)
+
+		if err != nil {
+			return err
+		}
+
+		index = elektra.ArrayIndex(n + 1)
+	}
+
+	if err := parentKey.SetMeta("warnings", index); err != nil {
+		return err
+	}
+
+	return setProblem(parentKey, "warnings/"+index, e.Number, e.Description, e.Reason, file, line)
+}
+
+// setErrorFromErr stores an error returned by the plugin on the parent Key,
+// unless the plugin already did that.
+func setErrorFromErr(parentKey elektra.Key, err error) {
+	if parentKey.Meta("error/number") != "" {
+		return
+	}
+
+	var elektraErr *elektra.ElektraError
+
+	if !errors.As(err, &elektraErr) {
+		elektraErr = elektra.NewElektraError(elektra.ErrPluginMisbehavior, err.Error())
+
+		for _, code := range codes {
+			if errors.Is(err, code) {
+				elektraErr = elektra.NewElektraError(code, err.Error())
+				break
+			}
+		}
+	}
+
+	_ = setProblem(parentKey, "error", elektraErr.Number, elektraErr.Description, err.Error(), "", 0)
+}
+
+func setProblem(parentKey elektra.Key, prefix, number, description, reason, file string, line int) error {
+	values := [][2]string{
+		{prefix, metaFormat},
+		{prefix + "/number", number},
+		{prefix + "/description", description},
+		{prefix + "/reason", reason},
+		{prefix + "/module", registered.contract.Name},
+		{prefix + "/file", file},
+		{prefix + "/line", strconv.Itoa(line)},
+		{prefix + "/mountpoint", parentKey.Name()},
+		{prefix + "/configfile", parentKey.String()},
+	}
+
+	for _, v := range values {
+		if err := parentKey.SetMeta(v[0], v[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
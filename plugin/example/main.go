@@ -0,0 +1,47 @@
+// Command example is an Elektra validation plugin written in Go.
+//
+// Build it with
+//
+//	go build -buildmode=c-shared -o libelektra-gonotempty.so ./plugin/example
+//
+// and copy the library to the plugin folder of Elektra, e.g. /usr/local/lib/elektra5.
+//
+// Keys with the meta Key `check/notempty` may then not be set to an empty value.
+package main
+
+import (
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/plugin"
+)
+
+type notEmpty struct{}
+
+func (p *notEmpty) Set(returned elektra.KeySet, parentKey elektra.Key) (plugin.Status, error) {
+	for _, k := range returned.ToSlice() {
+		if k.Meta("check/notempty") != "" && k.String() == "" {
+			if err := plugin.SetError(parentKey, elektra.ErrValidationSemantic, "key %q must not be empty", k.Name()); err != nil {
+				return plugin.StatusError, err
+			}
+
+			return plugin.StatusError, nil
+		}
+	}
+
+	return plugin.StatusSuccess, nil
+}
+
+func init() {
+	plugin.Register(elektra.PluginContract{
+		Name:        "gonotempty",
+		Provides:    []string{"check"},
+		Placements:  []string{"presetstorage"},
+		Status:      []string{"experimental"},
+		Description: "Validates that keys with check/notempty are not empty",
+		Licence:     "BSD",
+	}, func() interface{} {
+		return &notEmpty{}
+	})
+}
+
+// main is required by -buildmode=c-shared but never called.
+func main() {}
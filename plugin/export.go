@@ -0,0 +1,54 @@
+package plugin
+
+// #cgo pkg-config: elektra
+// #cgo LDFLAGS: -lelektra-plugin
+// #include <kdbplugin.h>
+import "C"
+
+import (
+	"unsafe"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// The functions below are called by `elektraPluginSymbol` in plugin.c
+// and by libelektra. They must not be renamed.
+
+//export goPluginName
+func goPluginName() *C.char {
+	// libelektra keeps the name for the lifetime of the plugin
+	return C.CString(registered.contract.Name)
+}
+
+//export goPluginOpen
+func goPluginOpen(handle *C.Plugin, errorKey *C.Key) C.int {
+	config := elektra.WrapKeySetPtr(unsafe.Pointer(C.elektraPluginGetConfig(handle)))
+
+	return C.int(onOpen(uintptr(unsafe.Pointer(handle)), config, elektra.WrapKeyPtr(unsafe.Pointer(errorKey))))
+}
+
+//export goPluginClose
+func goPluginClose(handle *C.Plugin, errorKey *C.Key) C.int {
+	return C.int(onClose(uintptr(unsafe.Pointer(handle)), elektra.WrapKeyPtr(unsafe.Pointer(errorKey))))
+}
+
+//export goPluginGet
+func goPluginGet(handle *C.Plugin, returned *C.KeySet, parentKey *C.Key) C.int {
+	ks, key := elektra.WrapKeySetPtr(unsafe.Pointer(returned)), elektra.WrapKeyPtr(unsafe.Pointer(parentKey))
+
+	return C.int(onGet(uintptr(unsafe.Pointer(handle)), ks, key))
+}
+
+//export goPluginSet
+func goPluginSet(handle *C.Plugin, returned *C.KeySet, parentKey *C.Key) C.int {
+	ks, key := elektra.WrapKeySetPtr(unsafe.Pointer(returned)), elektra.WrapKeyPtr(unsafe.Pointer(parentKey))
+
+	return C.int(onSet(uintptr(unsafe.Pointer(handle)), ks, key))
+}
+
+//export goPluginError
+func goPluginError(handle *C.Plugin, returned *C.KeySet, parentKey *C.Key) C.int {
+	ks, key := elektra.WrapKeySetPtr(unsafe.Pointer(returned)), elektra.WrapKeyPtr(unsafe.Pointer(parentKey))
+
+	return C.int(onError(uintptr(unsafe.Pointer(handle)), ks, key))
+}
@@ -0,0 +1,17 @@
+#include <kdbplugin.h>
+
+#include "_cgo_export.h"
+
+// elektraPluginSymbol is looked up by libelektra after loading
+// the shared library of the plugin.
+Plugin * elektraPluginSymbol (void)
+{
+	// clang-format off
+	return elektraPluginExport (goPluginName (),
+		ELEKTRA_PLUGIN_OPEN,	&goPluginOpen,
+		ELEKTRA_PLUGIN_CLOSE,	&goPluginClose,
+		ELEKTRA_PLUGIN_GET,	&goPluginGet,
+		ELEKTRA_PLUGIN_SET,	&goPluginSet,
+		ELEKTRA_PLUGIN_ERROR,	&goPluginError,
+		ELEKTRA_PLUGIN_END);
+}
@@ -0,0 +1,226 @@
+// Package plugin allows to write Elektra plugins in Go.
+//
+// A plugin is a `main` package that registers its implementation
+// in an `init` function and is built as a shared library, e.g.:
+//
+//	go build -buildmode=c-shared -o libelektra-myplugin.so ./myplugin
+//
+// The library exports `elektraPluginSymbol`, so libelektra can load it
+// like any other plugin once it is installed into the plugin folder.
+//
+// Such a plugin contains its own Go runtime and must not be loaded into a
+// process that already runs one: Go programs using libelektra, e.g. kdb-go,
+// elektra-http or any program built with this module, crash or deadlock
+// when they mount or use a Go plugin. Use it from C programs like `kdb`.
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// Status is returned by the Get, Set and Error functions of a plugin.
+type Status int
+
+const (
+	StatusError    Status = -1
+	StatusNoUpdate Status = 0
+	StatusSuccess  Status = 1
+)
+
+// Opener is implemented by plugins that need to initialize themselves,
+// `config` is the configuration of the plugin.
+type Opener interface {
+	Open(config elektra.KeySet, errorKey elektra.Key) error
+}
+
+// Closer is implemented by plugins that need to free resources.
+type Closer interface {
+	Close(errorKey elektra.Key) error
+}
+
+// Getter is implemented by plugins that read or transform Keys on kdbGet.
+type Getter interface {
+	Get(returned elektra.KeySet, parentKey elektra.Key) (Status, error)
+}
+
+// Setter is implemented by plugins that validate or write Keys on kdbSet.
+type Setter interface {
+	Set(returned elektra.KeySet, parentKey elektra.Key) (Status, error)
+}
+
+// ErrorHandler is implemented by plugins that need to roll back
+// changes if kdbSet failed.
+type ErrorHandler interface {
+	Error(returned elektra.KeySet, parentKey elektra.Key) (Status, error)
+}
+
+// Factory creates a new instance of the plugin, it is called
+// every time libelektra opens the plugin. The instance should implement
+// at least one of Opener, Closer, Getter, Setter and ErrorHandler.
+type Factory func() interface{}
+
+var (
+	ErrNotRegistered = errors.New("no plugin registered")
+
+	registered struct {
+		contract elektra.PluginContract
+		factory  Factory
+	}
+
+	mutex     sync.Mutex
+	instances = map[uintptr]interface{}{}
+)
+
+// Register registers the plugin described by `contract` which instances
+// are created by `factory`. It has to be called once, from an `init` function.
+func Register(contract elektra.PluginContract, factory Factory) {
+	registered.contract = contract
+	registered.factory = factory
+}
+
+func instance(handle uintptr) interface{} {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return instances[handle]
+}
+
+func onOpen(handle uintptr, config elektra.KeySet, errorKey elektra.Key) Status {
+	if registered.factory == nil {
+		setErrorFromErr(errorKey, ErrNotRegistered)
+		return StatusError
+	}
+
+	p := registered.factory()
+
+	mutex.Lock()
+	instances[handle] = p
+	mutex.Unlock()
+
+	if opener, ok := p.(Opener); ok {
+		if err := opener.Open(config, errorKey); err != nil {
+			setErrorFromErr(errorKey, err)
+			return StatusError
+		}
+	}
+
+	return StatusSuccess
+}
+
+func onClose(handle uintptr, errorKey elektra.Key) Status {
+	p := instance(handle)
+
+	mutex.Lock()
+	delete(instances, handle)
+	mutex.Unlock()
+
+	if closer, ok := p.(Closer); ok {
+		if err := closer.Close(errorKey); err != nil {
+			setErrorFromErr(errorKey, err)
+			return StatusError
+		}
+	}
+
+	return StatusSuccess
+}
+
+func onGet(handle uintptr, returned elektra.KeySet, parentKey elektra.Key) Status {
+	if parentKey.Name() == contractKeyName() {
+		if err := appendContract(returned); err != nil {
+			setErrorFromErr(parentKey, err)
+			return StatusError
+		}
+
+		return StatusSuccess
+	}
+
+	getter, ok := instance(handle).(Getter)
+
+	if !ok {
+		return StatusNoUpdate
+	}
+
+	status, err := getter.Get(returned, parentKey)
+
+	return result(parentKey, status, err)
+}
+
+func onSet(handle uintptr, returned elektra.KeySet, parentKey elektra.Key) Status {
+	setter, ok := instance(handle).(Setter)
+
+	if !ok {
+		return StatusNoUpdate
+	}
+
+	status, err := setter.Set(returned, parentKey)
+
+	return result(parentKey, status, err)
+}
+
+func onError(handle uintptr, returned elektra.KeySet, parentKey elektra.Key) Status {
+	errorHandler, ok := instance(handle).(ErrorHandler)
+
+	if !ok {
+		return StatusNoUpdate
+	}
+
+	status, err := errorHandler.Error(returned, parentKey)
+
+	return result(parentKey, status, err)
+}
+
+// result converts the return values of a plugin function into the Status
+// returned to libelektra, errors are stored on the parent Key.
+func result(parentKey elektra.Key, status Status, err error) Status {
+	if err != nil {
+		setErrorFromErr(parentKey, err)
+		return StatusError
+	}
+
+	return status
+}
+
+func contractKeyName() string {
+	return "system:/elektra/modules/" + registered.contract.Name
+}
+
+// appendContract appends the contract of the registered plugin below
+// "system:/elektra/modules/<name>", where libelektra looks for it.
+func appendContract(returned elektra.KeySet) error {
+	c := registered.contract
+	root := contractKeyName()
+
+	values := map[string]string{
+		root:                        "",
+		root + "/infos":             "",
+		root + "/infos/provides":    strings.Join(c.Provides, " "),
+		root + "/infos/needs":       strings.Join(c.Needs, " "),
+		root + "/infos/recommends":  strings.Join(c.Recommends, " "),
+		root + "/infos/placements":  strings.Join(c.Placements, " "),
+		root + "/infos/status":      strings.Join(c.Status, " "),
+		root + "/infos/description": c.Description,
+		root + "/infos/author":      c.Author,
+		root + "/infos/licence":     c.Licence,
+		root + "/infos/version":     "1",
+	}
+
+	for name, value := range c.Config {
+		values[root+"/config/needs/"+name] = value
+	}
+
+	for name, value := range values {
+		k, err := elektra.NewKey(name, value)
+
+		if err != nil {
+			return err
+		}
+
+		returned.AppendKey(k)
+	}
+
+	return nil
+}
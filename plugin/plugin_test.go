@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	. "go.libelektra.org/test"
+)
+
+func TestSetError(t *testing.T) {
+	parentKey, err := elektra.NewKey("user:/tests/go/elektra/plugin", "/tmp/plugin.ecf")
+	Check(t, err, "could not create key")
+	defer parentKey.Close()
+
+	err = SetError(parentKey, elektra.ErrValidationSemantic, "value %q is invalid", "foo")
+	Check(t, err, "could not set error")
+
+	Assert(t, parentKey.Meta("error") == metaFormat, "error meta Key has wrong format")
+	Assert(t, parentKey.Meta("error/number") == "C03200", "wrong error number")
+	Assert(t, parentKey.Meta("error/description") == "ValidationSemantic", "wrong error description")
+	Assert(t, parentKey.Meta("error/reason") == `value "foo" is invalid`, "wrong error reason")
+	Assert(t, parentKey.Meta("error/mountpoint") == "user:/tests/go/elektra/plugin", "wrong error mountpoint")
+	Assert(t, parentKey.Meta("error/configfile") == "/tmp/plugin.ecf", "wrong error configfile")
+	Assert(t, parentKey.Meta("error/line") != "0", "error line is missing")
+}
+
+func TestAddWarning(t *testing.T) {
+	parentKey, err := elektra.NewKey("user:/tests/go/elektra/plugin")
+	Check(t, err, "could not create key")
+	defer parentKey.Close()
+
+	for i := 0; i < 12; i++ {
+		err = AddWarning(parentKey, elektra.ErrResource, "warning %d", i)
+		Check(t, err, "could not add warning")
+	}
+
+	Assertf(t, parentKey.Meta("warnings") == "#_11", "last warning should be #_11 but is %q", parentKey.Meta("warnings"))
+	Assert(t, parentKey.Meta("warnings/#0/reason") == "warning 0", "wrong reason of first warning")
+	Assert(t, parentKey.Meta("warnings/#_10/number") == "C01100", "wrong number of warning #_10")
+}
+
+var errorCodeTests = []struct {
+	err    error
+	number string
+}{
+	{fmt.Errorf("%w: invalid port", elektra.ErrValidationSyntactic), "C03100"},
+	{errors.New("unexpected"), "C01330"},
+	{&elektra.ElektraError{Number: "C01110", Description: "OutOfMemory"}, "C01110"},
+}
+
+func TestSetErrorFromErr(t *testing.T) {
+	for _, test := range errorCodeTests {
+		parentKey, err := elektra.NewKey("user:/tests/go/elektra/plugin")
+		Check(t, err, "could not create key")
+
+		setErrorFromErr(parentKey, test.err)
+		Assertf(t, parentKey.Meta("error/number") == test.number,
+			"error %v should have number %s but has %s", test.err, test.number, parentKey.Meta("error/number"))
+
+		parentKey.Close()
+	}
+}
+
+func TestGetContract(t *testing.T) {
+	Register(elektra.PluginContract{
+		Name:       "gotest",
+		Provides:   []string{"check"},
+		Placements: []string{"presetstorage", "postgetstorage"},
+	}, func() interface{} { return nil })
+
+	parentKey, err := elektra.NewKey("system:/elektra/modules/gotest")
+	Check(t, err, "could not create key")
+	defer parentKey.Close()
+
+	ks := elektra.NewKeySet()
+	defer ks.Close()
+
+	status := onGet(0, ks, parentKey)
+	Assert(t, status == StatusSuccess, "getting the contract should succeed")
+
+	placements := ks.LookupByName("system:/elektra/modules/gotest/infos/placements")
+	Assert(t, placements != nil, "contract does not contain placements")
+	Assertf(t, placements.String() == "presetstorage postgetstorage", "wrong placements %q", placements.String())
+}
@@ -0,0 +1,230 @@
+// Package reload keeps a decoded configuration of a service up to date,
+// new configurations are validated before they replace the current one.
+package reload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid configuration")
+	ErrClosed        = errors.New("manager is closed")
+)
+
+// DecodeFunc decodes the Keys below `parentKey` into a configuration.
+type DecodeFunc[T any] func(ks elektra.KeySet, parentKey elektra.Key) (*T, error)
+
+// ValidateFunc checks a decoded configuration before it is used.
+type ValidateFunc[T any] func(config *T) error
+
+// Manager loads the configuration below a parent Key and reloads it on request.
+// Current can be called concurrently with reloads.
+type Manager[T any] struct {
+	handle    elektra.KDB
+	parentKey elektra.Key
+	decode    DecodeFunc[T]
+	validate  []ValidateFunc[T]
+
+	current atomic.Pointer[T]
+	notify  chan struct{}
+	done    chan struct{}
+
+	// mu serializes reloads and guards the fields below
+	mu          sync.Mutex
+	closed      bool
+	keys        elektra.KeySet
+	subscribers []func(old, new *T)
+	errHandlers []func(err error)
+}
+
+// New loads the initial configuration, which has to be valid.
+func New[T any](handle elektra.KDB, parentKey elektra.Key, decode DecodeFunc[T], validate ...ValidateFunc[T]) (*Manager[T], error) {
+	m := &Manager[T]{
+		handle:    handle,
+		parentKey: parentKey,
+		decode:    decode,
+		validate:  validate,
+		notify:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+
+	if _, err := m.Reload(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Close stops Watch and frees the Keys of the last reload. Current still
+// returns the last configuration, later reloads fail with ErrClosed.
+func (m *Manager[T]) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+
+	m.closed = true
+	close(m.done)
+
+	if m.keys != nil {
+		m.keys.Close()
+		m.keys = nil
+	}
+}
+
+// Current returns the current configuration.
+func (m *Manager[T]) Current() *T {
+	return m.current.Load()
+}
+
+// Subscribe registers `fn` to be called after a new configuration was swapped in.
+// It is called during the reload, so it must not call Reload itself.
+func (m *Manager[T]) Subscribe(fn func(old, new *T)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// OnError registers `fn` to be called if a reload triggered by Watch fails.
+func (m *Manager[T]) OnError(fn func(err error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.errHandlers = append(m.errHandlers, fn)
+}
+
+// Reload loads, decodes and validates the configuration and swaps it in.
+// If any step fails the previous configuration is kept. Returns false
+// if the Keys did not change since the last reload. Errors of decode and
+// validate are wrapped in ErrInvalidConfig.
+func (m *Manager[T]) Reload() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return false, ErrClosed
+	}
+
+	// kdbGet leaves the KeySet untouched if nothing changed,
+	// so the Keys of the last reload have to be passed again
+	var ks elektra.KeySet
+
+	if m.keys != nil {
+		ks = m.keys.Duplicate()
+	} else {
+		ks = elektra.NewKeySet()
+	}
+
+	if _, err := m.handle.Get(ks, m.parentKey); err != nil {
+		ks.Close()
+		return false, err
+	}
+
+	if m.keys != nil && m.keys.Equal(ks, true) {
+		ks.Close()
+		return false, nil
+	}
+
+	config, err := m.decode(ks, m.parentKey)
+
+	if err == nil && config == nil {
+		err = errors.New("decode returned no configuration")
+	}
+
+	if err == nil {
+		for _, validate := range m.validate {
+			if err = validate(config); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		ks.Close()
+		return false, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	if m.keys != nil {
+		m.keys.Close()
+	}
+
+	m.keys = ks
+	old := m.current.Swap(config)
+
+	for _, fn := range m.subscribers {
+		fn(old, config)
+	}
+
+	return true, nil
+}
+
+// Notify requests a reload from Watch, e.g. when a change notification
+// was received. It does not block.
+func (m *Manager[T]) Notify() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Watch reloads the configuration when Notify is called, every `interval`
+// if it is positive and when one of `signals` is received, SIGHUP by
+// default. It blocks until `ctx` is done or Close is called. Failed reloads
+// are passed to the handlers registered with OnError.
+func (m *Manager[T]) Watch(ctx context.Context, interval time.Duration, signals ...os.Signal) error {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, signals...)
+	defer signal.Stop(sig)
+
+	var tick <-chan time.Time
+
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.done:
+			return nil
+		case <-sig:
+		case <-tick:
+		case <-m.notify:
+		}
+
+		if _, err := m.Reload(); err != nil {
+			m.handleError(err)
+		}
+	}
+}
+
+func (m *Manager[T]) handleError(err error) {
+	m.mu.Lock()
+	handlers := append([]func(error){}, m.errHandlers...)
+	m.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(err)
+	}
+}
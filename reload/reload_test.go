@@ -0,0 +1,215 @@
+package reload_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/kdb/kdbtest"
+	"go.libelektra.org/reload"
+	. "go.libelektra.org/test"
+)
+
+type config struct {
+	Port int
+}
+
+const parentName = "user:/tests/go/reload"
+
+func decode(ks elektra.KeySet, parentKey elektra.Key) (*config, error) {
+	key := ks.LookupByName(parentKey.Name() + "/port")
+
+	if key == nil {
+		return nil, errors.New("port is missing")
+	}
+
+	port, err := strconv.Atoi(key.String())
+
+	return &config{Port: port}, err
+}
+
+func validPort(c *config) error {
+	if c.Port <= 0 {
+		return errors.New("port must be positive")
+	}
+
+	return nil
+}
+
+func setPort(t *testing.T, mock *kdbtest.Mock, port string) {
+	t.Helper()
+
+	parentKey, _ := elektra.NewKey(parentName)
+	_, err := mock.Set(kdbtest.KeySetFromMap(map[string]string{parentName + "/port": port}), parentKey)
+	Check(t, err, "could not set port")
+}
+
+func newManager(t *testing.T) (*kdbtest.Mock, *reload.Manager[config]) {
+	t.Helper()
+
+	mock := kdbtest.NewMock(t)
+	setPort(t, mock, "80")
+
+	parentKey, _ := elektra.NewKey(parentName)
+	m, err := reload.New(mock, parentKey, decode, validPort)
+	Check(t, err, "could not create Manager")
+	t.Cleanup(m.Close)
+
+	return mock, m
+}
+
+func TestReload(t *testing.T) {
+	mock, m := newManager(t)
+	Assertf(t, m.Current().Port == 80, "port should be 80 but is %d", m.Current().Port)
+
+	var notified *config
+	m.Subscribe(func(old, new *config) {
+		notified = new
+	})
+
+	changed, err := m.Reload()
+	Check(t, err, "Reload failed")
+	Assert(t, !changed && notified == nil, "unchanged Keys should not be reloaded")
+
+	setPort(t, mock, "8080")
+
+	changed, err = m.Reload()
+	Check(t, err, "Reload failed")
+	Assert(t, changed, "Reload should report the change")
+	Assert(t, m.Current().Port == 8080 && notified == m.Current(), "subscriber was not notified of the new config")
+}
+
+func TestReloadKeepsPrevious(t *testing.T) {
+	mock, m := newManager(t)
+	previous := m.Current()
+
+	setPort(t, mock, "-1")
+
+	_, err := m.Reload()
+	Assert(t, errors.Is(err, reload.ErrInvalidConfig), "invalid config should return ErrInvalidConfig")
+	Assert(t, m.Current() == previous, "invalid config should not be swapped in")
+
+	mock.Fail("Get", 0, kdbtest.Error(elektra.ErrResource, "backend unavailable"))
+
+	_, err = m.Reload()
+	Assert(t, errors.Is(err, elektra.ErrResource), "failed Get should be returned")
+	Assert(t, m.Current() == previous, "failed Get should keep the previous config")
+}
+
+func TestReloadErrors(t *testing.T) {
+	mock := kdbtest.NewMock(t)
+	setPort(t, mock, "80")
+
+	parentKey, _ := elektra.NewKey(parentName)
+
+	_, err := reload.New(mock, parentKey, func(elektra.KeySet, elektra.Key) (*config, error) {
+		return nil, nil
+	})
+	Assert(t, errors.Is(err, reload.ErrInvalidConfig), "a nil config should return ErrInvalidConfig")
+
+	errPort := errors.New("port not allowed")
+
+	_, err = reload.New(mock, parentKey, decode, func(*config) error {
+		return errPort
+	})
+	Assert(t, errors.Is(err, reload.ErrInvalidConfig), "a failed validation should return ErrInvalidConfig")
+	Assert(t, errors.Is(err, errPort), "the error of the validation should be wrapped")
+}
+
+func TestReloadKDB(t *testing.T) {
+	kdbtest.Isolate(t)
+	handle := kdbtest.Open(t)
+
+	parentKey, _ := elektra.NewKey(parentName)
+
+	setKDBPort := func(port string) {
+		t.Helper()
+
+		ks := elektra.NewKeySet()
+		defer ks.Close()
+
+		_, err := handle.Get(ks, parentKey)
+		Check(t, err, "could not Get")
+
+		key, _ := elektra.NewKey(parentName+"/port", port)
+		ks.AppendKey(key)
+
+		_, err = handle.Set(ks, parentKey)
+		Check(t, err, "could not set port")
+	}
+
+	setKDBPort("80")
+
+	m, err := reload.New(kdbtest.Open(t), parentKey, decode, validPort)
+	Check(t, err, "could not create Manager")
+	defer m.Close()
+
+	for i := 0; i < 3; i++ {
+		changed, err := m.Reload()
+		Check(t, err, "repeated Reload failed")
+		Assert(t, !changed, "unchanged Keys should not be reloaded")
+		Assertf(t, m.Current().Port == 80, "port should be 80 but is %d", m.Current().Port)
+	}
+
+	setKDBPort("8080")
+
+	changed, err := m.Reload()
+	Check(t, err, "Reload failed")
+	Assert(t, changed, "Reload should report the change")
+	Assertf(t, m.Current().Port == 8080, "port should be 8080 but is %d", m.Current().Port)
+}
+
+func TestWatch(t *testing.T) {
+	mock, m := newManager(t)
+
+	reloaded := make(chan *config, 1)
+	m.Subscribe(func(old, new *config) {
+		reloaded <- new
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- m.Watch(ctx, 0)
+	}()
+
+	setPort(t, mock, "443")
+	m.Notify()
+
+	select {
+	case c := <-reloaded:
+		Assertf(t, c.Port == 443, "port should be 443 but is %d", c.Port)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notify did not trigger a reload")
+	}
+
+	cancel()
+	Assert(t, errors.Is(<-done, context.Canceled), "Watch should return when the context is done")
+}
+
+func TestClose(t *testing.T) {
+	_, m := newManager(t)
+
+	done := make(chan error)
+
+	go func() {
+		done <- m.Watch(context.Background(), 0)
+	}()
+
+	m.Close()
+
+	select {
+	case err := <-done:
+		Check(t, err, "Watch should return nil after Close")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not stop Watch")
+	}
+
+	_, err := m.Reload()
+	Assert(t, errors.Is(err, reload.ErrClosed), "Reload after Close should return ErrClosed")
+	Assertf(t, m.Current().Port == 80, "Current should keep the last config but the port is %d", m.Current().Port)
+}
@@ -0,0 +1,11 @@
+package server_test
+
+import (
+	"testing"
+
+	"go.libelektra.org/kdb/kdbtest"
+)
+
+func TestMain(m *testing.M) {
+	kdbtest.Main(m)
+}
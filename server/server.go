@@ -0,0 +1,506 @@
+// Package server exposes the key database over HTTP, similar to
+// libelektra's elektrad.
+//
+// The following endpoints are provided, `<keyname>` is either a key name
+// with namespace like `user:/my/app` or a cascading name like `my/app`.
+// Every segment of the path is one part of the key name, a `/` within
+// a part has to be escaped as `%2F`:
+//
+//	GET    /kdb/<keyname>             the Key as JSON
+//	GET    /kdb/<keyname>?subtree     the Key and all Keys below it as JSON
+//	PUT    /kdb/<keyname>             sets the value of the Key to the request body
+//	DELETE /kdb/<keyname>[?recursive] removes the Key (and all Keys below it)
+//	GET    /meta/<keyname>            all meta Keys of the Key
+//	PUT    /meta/<keyname>?name=<m>   sets the meta Key <m> to the request body
+//	DELETE /meta/<keyname>?name=<m>   removes the meta Key <m>
+//
+// Responses carry an ETag derived from the result of Get. Modifying requests
+// honor `If-Match`, so clients can detect concurrent modifications.
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	elektra "go.libelektra.org/kdb"
+)
+
+// maxBodySize limits the size of values sent to the server.
+const maxBodySize = 1 << 20
+
+// Key is the JSON representation of a Key.
+type Key struct {
+	Name  string            `json:"name"`
+	Value string            `json:"value"`
+	Meta  map[string]string `json:"meta,omitempty"`
+}
+
+// Server is an http.Handler that serves the key database.
+type Server struct {
+	// the KDB handle may not be used concurrently
+	mutex  sync.Mutex
+	handle elektra.KDB
+}
+
+// New creates a Server that accesses the key database via the opened `handle`.
+func New(handle elektra.KDB) *Server {
+	return &Server{handle: handle}
+}
+
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, args ...interface{}) error {
+	return &httpError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the body is read before locking, so slow clients do not block others
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+
+	if err != nil {
+		writeError(w, errorf(http.StatusBadRequest, "could not read body: %v", err))
+		return
+	}
+
+	path := r.URL.EscapedPath()
+
+	var endpoint func(http.ResponseWriter, *http.Request, string, []byte) error
+
+	switch {
+	case strings.HasPrefix(path, "/kdb/"):
+		endpoint, path = s.serveKey, strings.TrimPrefix(path, "/kdb/")
+	case strings.HasPrefix(path, "/meta/"):
+		endpoint, path = s.serveMeta, strings.TrimPrefix(path, "/meta/")
+	default:
+		writeError(w, errorf(http.StatusNotFound, "unknown endpoint %q", r.URL.Path))
+		return
+	}
+
+	name, err := keyName(path)
+
+	if err == nil {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		err = endpoint(w, r, name, body)
+	}
+
+	if err != nil {
+		writeError(w, err)
+	}
+}
+
+// keyName converts the escaped path of a request to a key name,
+// names without namespace are cascading. Every segment of the path
+// is unescaped and escaped again as a part of the key name.
+func keyName(path string) (string, error) {
+	segments := strings.Split(path, "/")
+	name := "/"
+
+	if first, err := url.PathUnescape(segments[0]); err == nil && strings.HasSuffix(first, ":") {
+		name = first + "/"
+		segments = segments[1:]
+	}
+
+	parts := make([]string, 0, len(segments))
+
+	for _, segment := range segments {
+		part, err := url.PathUnescape(segment)
+
+		if err != nil {
+			return "", errorf(http.StatusBadRequest, "invalid path segment %q", segment)
+		}
+
+		if part != "" {
+			parts = append(parts, elektra.EscapeBaseName(part))
+		}
+	}
+
+	return name + strings.Join(parts, "/"), nil
+}
+
+func (s *Server) serveKey(w http.ResponseWriter, r *http.Request, name string, body []byte) error {
+	switch r.Method {
+	case http.MethodGet:
+		return s.getKey(w, r, name)
+	case http.MethodPut:
+		return s.putKey(w, r, name, body)
+	case http.MethodDelete:
+		return s.deleteKey(w, r, name)
+	}
+
+	w.Header().Set("Allow", "GET, PUT, DELETE")
+
+	return errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+}
+
+func (s *Server) serveMeta(w http.ResponseWriter, r *http.Request, name string, body []byte) error {
+	switch r.Method {
+	case http.MethodGet:
+		return s.getMeta(w, r, name)
+	case http.MethodPut, http.MethodDelete:
+		return s.modifyMeta(w, r, name, body)
+	}
+
+	w.Header().Set("Allow", "GET, PUT, DELETE")
+
+	return errorf(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+}
+
+// get `Get`s the Key `name` and all Keys below it,
+// the caller has to close the KeySet and the parent Key.
+func (s *Server) get(name string) (elektra.KeySet, elektra.Key, error) {
+	parentKey, err := elektra.NewKey(name)
+
+	if err != nil {
+		return nil, nil, errorf(http.StatusBadRequest, "invalid key name %q", name)
+	}
+
+	ks := elektra.NewKeySet()
+
+	if _, err = s.handle.Get(ks, parentKey); err != nil {
+		ks.Close()
+		parentKey.Close()
+
+		return nil, nil, err
+	}
+
+	return ks, parentKey, nil
+}
+
+func (s *Server) getKey(w http.ResponseWriter, r *http.Request, name string) error {
+	ks, parentKey, err := s.get(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	if _, subtree := r.URL.Query()["subtree"]; subtree {
+		keys := below(ks, parentKey)
+
+		if len(keys) == 0 {
+			return errorf(http.StatusNotFound, "no keys below %q", name)
+		}
+
+		result := make([]Key, len(keys))
+
+		for i, k := range keys {
+			result[i] = toKey(k)
+		}
+
+		return writeJSON(w, r, http.StatusOK, ETag(keys...), result)
+	}
+
+	key := ks.LookupByName(parentKey.Name())
+
+	if key == nil {
+		return errorf(http.StatusNotFound, "key %q not found", name)
+	}
+
+	return writeJSON(w, r, http.StatusOK, ETag(key), toKey(key))
+}
+
+func (s *Server) putKey(w http.ResponseWriter, r *http.Request, name string, body []byte) error {
+	ks, parentKey, err := s.get(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	key := ks.LookupByName(parentKey.Name())
+
+	if err = checkPrecondition(r, key); err != nil {
+		return err
+	}
+
+	status := http.StatusOK
+
+	if key == nil {
+		keyName := parentKey.Name()
+
+		// like kdb, cascading keys are created in the user namespace
+		if parentKey.Namespace() == elektra.KEY_NS_CASCADING {
+			keyName = elektra.KEY_NS_USER.KeyName(keyName)
+		}
+
+		if key, err = elektra.NewKey(keyName, string(body)); err != nil {
+			return errorf(http.StatusBadRequest, "invalid key name %q", keyName)
+		}
+
+		ks.AppendKey(key)
+		status = http.StatusCreated
+	} else if err = key.SetString(string(body)); err != nil {
+		return err
+	}
+
+	if _, err = s.handle.Set(ks, parentKey); err != nil {
+		return err
+	}
+
+	return writeJSON(w, r, status, ETag(key), toKey(key))
+}
+
+func (s *Server) deleteKey(w http.ResponseWriter, r *http.Request, name string) error {
+	ks, parentKey, err := s.get(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	if _, recursive := r.URL.Query()["recursive"]; recursive {
+		keys := below(ks, parentKey)
+
+		if len(keys) == 0 {
+			return errorf(http.StatusNotFound, "no keys below %q", name)
+		}
+
+		if err = checkETag(r, ETag(keys...)); err != nil {
+			return err
+		}
+
+		if err = elektra.RemoveTree(ks, parentKey); err != nil {
+			return err
+		}
+	} else {
+		key := ks.LookupByName(parentKey.Name())
+
+		if key == nil {
+			return errorf(http.StatusNotFound, "key %q not found", name)
+		}
+
+		if err = checkPrecondition(r, key); err != nil {
+			return err
+		}
+
+		ks.Remove(key).Close()
+	}
+
+	if _, err = s.handle.Set(ks, parentKey); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+func (s *Server) getMeta(w http.ResponseWriter, r *http.Request, name string) error {
+	ks, parentKey, err := s.get(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	key := ks.LookupByName(parentKey.Name())
+
+	if key == nil {
+		return errorf(http.StatusNotFound, "key %q not found", name)
+	}
+
+	return writeJSON(w, r, http.StatusOK, ETag(key), key.MetaMap())
+}
+
+func (s *Server) modifyMeta(w http.ResponseWriter, r *http.Request, name string, body []byte) error {
+	metaName := r.URL.Query().Get("name")
+
+	if metaName == "" {
+		return errorf(http.StatusBadRequest, "the query parameter `name` is missing")
+	}
+
+	ks, parentKey, err := s.get(name)
+
+	if err != nil {
+		return err
+	}
+
+	defer ks.Close()
+	defer parentKey.Close()
+
+	key := ks.LookupByName(parentKey.Name())
+
+	if key == nil {
+		return errorf(http.StatusNotFound, "key %q not found", name)
+	}
+
+	if err = checkPrecondition(r, key); err != nil {
+		return err
+	}
+
+	if r.Method == http.MethodDelete {
+		if _, ok := key.MetaMap()[metaName]; !ok {
+			return errorf(http.StatusNotFound, "meta key %q of %q not found", metaName, name)
+		}
+
+		err = key.RemoveMeta(metaName)
+	} else {
+		err = key.SetMeta(metaName, string(body))
+	}
+
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid meta key %q: %v", metaName, err)
+	}
+
+	if _, err = s.handle.Set(ks, parentKey); err != nil {
+		return err
+	}
+
+	return writeJSON(w, r, http.StatusOK, ETag(key), key.MetaMap())
+}
+
+// below returns the Key `parentKey` and all Keys below it.
+func below(ks elektra.KeySet, parentKey elektra.Key) []elektra.Key {
+	var keys []elektra.Key
+
+	for _, k := range ks.ToSlice() {
+		if k.IsBelowOrSame(parentKey) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// checkPrecondition compares the `If-Match` header with the ETag of `key`,
+// which is nil if the Key does not exist.
+func checkPrecondition(r *http.Request, key elektra.Key) error {
+	if key == nil {
+		if r.Header.Get("If-Match") != "" {
+			return errorf(http.StatusPreconditionFailed, "the key does not exist")
+		}
+
+		return nil
+	}
+
+	return checkETag(r, ETag(key))
+}
+
+// checkETag compares the `If-Match` header with the current `etag`.
+func checkETag(r *http.Request, etag string) error {
+	ifMatch := r.Header.Get("If-Match")
+
+	if ifMatch == "" || ifMatch == "*" || ifMatch == etag {
+		return nil
+	}
+
+	return errorf(http.StatusPreconditionFailed, "the key was modified")
+}
+
+// ETag computes an entity tag from the names, values and meta Keys of `keys`.
+func ETag(keys ...elektra.Key) string {
+	h := sha256.New()
+
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", k.Name(), k.String())
+
+		meta := k.MetaMap()
+		names := make([]string, 0, len(meta))
+
+		for name := range meta {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(h, "%s\x00%s\x00", name, meta[name])
+		}
+
+		h.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%q", fmt.Sprintf("%x", h.Sum(nil)[:16]))
+}
+
+func toKey(k elektra.Key) Key {
+	meta := k.MetaMap()
+
+	if len(meta) == 0 {
+		meta = nil
+	}
+
+	return Key{Name: k.Name(), Value: k.String(), Meta: meta}
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, etag string, v interface{}) error {
+	w.Header().Set("ETag", etag)
+
+	if r.Method == http.MethodGet && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(v)
+}
+
+// StatusCode maps errors to HTTP status codes,
+// errors of Elektra are mapped according to their category.
+func StatusCode(err error) int {
+	var httpErr *httpError
+
+	switch {
+	case errors.As(err, &httpErr):
+		return httpErr.status
+	case errors.Is(err, elektra.ErrConflictingState):
+		return http.StatusConflict
+	case errors.Is(err, elektra.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, elektra.ErrResource):
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	body := map[string]interface{}{"message": err.Error()}
+
+	var elektraErr *elektra.ElektraError
+
+	if errors.As(err, &elektraErr) {
+		body["number"] = elektraErr.Number
+		body["description"] = elektraErr.Description
+		body["reason"] = elektraErr.Reason
+		body["ingroup"] = elektraErr.Ingroup
+		body["module"] = elektraErr.Module
+		body["file"] = elektraErr.File
+		body["line"] = elektraErr.Line
+		body["mountpoint"] = elektraErr.Mountpoint
+		body["configfile"] = elektraErr.ConfigFile
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(StatusCode(err))
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": body})
+}
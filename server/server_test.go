@@ -0,0 +1,113 @@
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	elektra "go.libelektra.org/kdb"
+	"go.libelektra.org/server"
+	. "go.libelektra.org/test"
+)
+
+func request(t *testing.T, s http.Handler, method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+
+	for name, value := range header {
+		r.Header.Set(name, value)
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestServer(t *testing.T) {
+	handle := elektra.New()
+	err := handle.Open()
+	Check(t, err, "could not open KDB")
+	defer handle.Close()
+
+	s := server.New(handle)
+	path := "/kdb/user:/tests/go/elektra/server/key"
+
+	_ = request(t, s, http.MethodDelete, "/kdb/user:/tests/go/elektra/server?recursive", "", nil)
+
+	w := request(t, s, http.MethodPut, path, "Hello World", nil)
+	Assertf(t, w.Code == http.StatusCreated, "PUT should create the key, got status %d: %s", w.Code, w.Body)
+	etag := w.Header().Get("ETag")
+	Assert(t, etag != "", "PUT did not return an ETag")
+
+	w = request(t, s, http.MethodGet, path, "", nil)
+	Assertf(t, w.Code == http.StatusOK, "GET failed with status %d: %s", w.Code, w.Body)
+	Assert(t, w.Header().Get("ETag") == etag, "GET returned a different ETag")
+
+	var key server.Key
+	err = json.Unmarshal(w.Body.Bytes(), &key)
+	Check(t, err, "could not decode key")
+	Assertf(t, key.Value == "Hello World", "wrong value %q", key.Value)
+
+	w = request(t, s, http.MethodGet, path, "", map[string]string{"If-None-Match": etag})
+	Assertf(t, w.Code == http.StatusNotModified, "GET with If-None-Match should return 304 but returned %d", w.Code)
+
+	w = request(t, s, http.MethodPut, "/meta/user:/tests/go/elektra/server/key?name=check/type", "string", map[string]string{"If-Match": etag})
+	Assertf(t, w.Code == http.StatusOK, "PUT meta failed with status %d: %s", w.Code, w.Body)
+
+	w = request(t, s, http.MethodPut, path, "Stale", map[string]string{"If-Match": etag})
+	Assertf(t, w.Code == http.StatusPreconditionFailed, "PUT with stale ETag should fail but returned %d", w.Code)
+
+	w = request(t, s, http.MethodGet, "/meta/user:/tests/go/elektra/server/key", "", nil)
+	Assertf(t, w.Code == http.StatusOK, "GET meta failed with status %d: %s", w.Code, w.Body)
+	Assertf(t, strings.Contains(w.Body.String(), `"check/type":"string"`), "meta not returned: %s", w.Body)
+
+	w = request(t, s, http.MethodGet, "/kdb/user:/tests/go/elektra/server?subtree", "", nil)
+	Assertf(t, w.Code == http.StatusOK, "GET subtree failed with status %d: %s", w.Code, w.Body)
+
+	var keys []server.Key
+	err = json.Unmarshal(w.Body.Bytes(), &keys)
+	Check(t, err, "could not decode keys")
+	Assertf(t, len(keys) == 1, "subtree should contain 1 key but contains %d", len(keys))
+
+	w = request(t, s, http.MethodDelete, path, "", nil)
+	Assertf(t, w.Code == http.StatusNoContent, "DELETE failed with status %d: %s", w.Code, w.Body)
+
+	w = request(t, s, http.MethodGet, path, "", nil)
+	Assertf(t, w.Code == http.StatusNotFound, "GET of deleted key should return 404 but returned %d", w.Code)
+
+	w = request(t, s, http.MethodPut, "/kdb/user:/tests/go/elektra/server/a%2Fb", "escaped", nil)
+	Assertf(t, w.Code == http.StatusCreated, "PUT with an escaped slash failed with status %d: %s", w.Code, w.Body)
+
+	err = json.Unmarshal(w.Body.Bytes(), &key)
+	Check(t, err, "could not decode key")
+	Assertf(t, key.Name == `user:/tests/go/elektra/server/a\/b`, "an escaped slash should stay in the base name: %q", key.Name)
+
+	w = request(t, s, http.MethodDelete, "/kdb/user:/tests/go/elektra/server/a%2Fb", "", nil)
+	Assertf(t, w.Code == http.StatusNoContent, "DELETE with an escaped slash failed with status %d: %s", w.Code, w.Body)
+}
+
+var statusCodeTests = []struct {
+	err      error
+	expected int
+}{
+	{&elektra.ElektraError{Number: "C02000", Err: elektra.ErrConflictingState}, http.StatusConflict},
+	{&elektra.ElektraError{Number: "C03100", Err: elektra.ErrValidationSyntactic}, http.StatusUnprocessableEntity},
+	{&elektra.ElektraError{Number: "C01110", Err: elektra.ErrOutOfMemory}, http.StatusServiceUnavailable},
+	{&elektra.ElektraError{Number: "C01310", Err: elektra.ErrInternal}, http.StatusInternalServerError},
+	{errors.New("unknown"), http.StatusInternalServerError},
+}
+
+func TestStatusCode(t *testing.T) {
+	for _, test := range statusCodeTests {
+		t.Run(fmt.Sprint(test.err), func(t *testing.T) {
+			status := server.StatusCode(test.err)
+			Assertf(t, status == test.expected, "status should be %d but is %d", test.expected, status)
+		})
+	}
+}